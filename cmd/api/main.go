@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/authz"
+	"employees-api/internal/config"
+	"employees-api/internal/database"
+	"employees-api/internal/metrics"
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+	"employees-api/internal/tracing"
+	"employees-api/internal/transport"
+	"employees-api/internal/transport/grpcapi"
+
+	"google.golang.org/grpc"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		transport.NewLogger().Error("ошибка_конфигурации", slog.String(transport.LogKeyError, err.Error()))
+		os.Exit(1)
+	}
+
+	logOptions := []transport.LoggerOption{
+		transport.WithLogLevel(cfg.LogLevel),
+		transport.WithLogFormat(cfg.LogFormat),
+		transport.WithFieldLanguage(cfg.LogFieldLanguage),
+	}
+	if cfg.LogClearFields != nil {
+		logOptions = append(logOptions, transport.WithClearFields(cfg.LogClearFields...))
+	}
+	logger := transport.NewLogger(logOptions...)
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error("сервер_завершился_с_ошибкой", slog.String(transport.LogKeyError, err.Error()))
+		os.Exit(1)
+	}
+}
+
+func run(cfg *config.Config, logger *transport.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("ошибка_остановки_трассировки", slog.String(transport.LogKeyError, err.Error()))
+		}
+	}()
+
+	m := metrics.New()
+
+	pool, err := database.NewPool(ctx, cfg, database.WithQueryObserver(m.ObserveQuery))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pool.Close()
+		logger.Info("пул_соединений_закрыт")
+	}()
+
+	if cfg.RunMigrations {
+		if err := database.RunMigrations(ctx, pool, cfg.MigrationsDir); err != nil {
+			return err
+		}
+		logger.Info("миграции_применены", slog.String(transport.LogKeyDirectory, cfg.MigrationsDir))
+	}
+
+	m.RegisterPool(pool)
+
+	repo := repository.NewEmployeeRepository(pool)
+	idempotencyRepo := repository.NewIdempotencyRepository(pool)
+	svc := service.NewEmployeeService(repo, service.WithSearchThreshold(cfg.SearchThreshold))
+	imports := service.NewImportService(repo, repository.NewImportJobRepository(pool), cfg.ImportJobTimeout,
+		service.WithImportLogger(logger.Logger),
+	)
+
+	if failed, err := imports.FailStale(ctx); err != nil {
+		return err
+	} else if failed > 0 {
+		logger.Info("прерванные_импорты_завершены", slog.Int64(transport.LogKeyCount, failed))
+	}
+
+	var background sync.WaitGroup
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer func() {
+		stopBackground()
+		background.Wait()
+	}()
+
+	var (
+		employees     service.Employees = svc
+		importService service.Imports   = imports
+		apiKeys       service.APIKeys
+	)
+	if cfg.APIKeysEnabled {
+		apiKeys = service.NewAPIKeyService(repository.NewAPIKeyRepository(pool))
+	}
+	if cfg.AuthzPolicyFile != "" {
+		policy, err := authz.LoadPolicyFile(cfg.AuthzPolicyFile)
+		if err != nil {
+			return err
+		}
+		employees = authz.NewEmployeeService(svc, policy)
+		importService = authz.NewImportService(imports, policy)
+		if apiKeys != nil {
+			apiKeys = authz.NewAPIKeyService(apiKeys, policy)
+		}
+
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			runPolicyReloader(backgroundCtx, policy, cfg.AuthzReload, logger)
+		}()
+	}
+
+	handlerOptions := []transport.HandlerOption{
+		transport.WithIdempotency(idempotencyRepo, cfg.IdempotencyTTL),
+		transport.WithImports(importService, cfg.ImportMaxBytes),
+		transport.WithExportTimeout(cfg.ExportTimeout),
+		transport.WithMetrics(m),
+		transport.WithErrorFormat(cfg.ErrorFormat),
+	}
+	if cfg.GraphQLEnabled {
+		handlerOptions = append(handlerOptions, transport.WithGraphQL(cfg.GraphQLMaxCost))
+	}
+	var grpcOptions []grpcapi.Option
+	if cfg.AuthEnabled {
+		authenticator, err := newAuthenticator(ctx, cfg, logger)
+		if err != nil {
+			return err
+		}
+		handlerOptions = append(handlerOptions, transport.WithAuthentication(authenticator, cfg.AuthExemptPaths...))
+		grpcOptions = append(grpcOptions, grpcapi.WithAuthentication(authenticator))
+	}
+	if apiKeys != nil {
+		handlerOptions = append(handlerOptions, transport.WithAPIKeys(apiKeys))
+		grpcOptions = append(grpcOptions, grpcapi.WithAPIKeys(apiKeys))
+	}
+	grpcOptions = append(grpcOptions, grpcapi.WithMetrics(m))
+	if cfg.RateLimitEnabled {
+		// Одно хранилище на оба транспорта: клиент не удваивает лимит, переходя с REST на gRPC.
+		rateLimitStore := ratelimit.NewMemoryStore()
+		rateLimits := ratelimit.NewRules(cfg.RateLimitDefault, cfg.RateLimitRules...)
+		handlerOptions = append(handlerOptions,
+			transport.WithRateLimit(rateLimitStore, rateLimits, cfg.TrustedProxies...),
+			transport.WithIPRateLimit(cfg.RateLimitPerIP),
+		)
+		grpcOptions = append(grpcOptions, grpcapi.WithRateLimit(rateLimitStore, rateLimits, cfg.RateLimitPerIP))
+	}
+
+	handler := transport.NewHandler(employees, logger, handlerOptions...)
+
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		runIdempotencySweeper(backgroundCtx, idempotencyRepo, cfg.IdempotencySweep, logger)
+	}()
+
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      handler.Routes(),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+
+	serverErr := make(chan error, 2)
+	go func() {
+		logger.Info("сервер_запускается", slog.String(transport.LogKeyPort, cfg.Port))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	var (
+		grpcAPI    *grpcapi.Server
+		grpcServer *grpc.Server
+	)
+	if cfg.GRPCEnabled {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			return err
+		}
+		grpcAPI = grpcapi.NewServer(employees, logger, grpcOptions...)
+		grpcServer = grpcAPI.GRPCServer()
+		go func() {
+			logger.Info("grpc_сервер_запускается", slog.String(transport.LogKeyPort, cfg.GRPCPort))
+			if err := grpcServer.Serve(listener); err != nil {
+				serverErr <- err
+			}
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("сервер_останавливается", slog.Int64(transport.LogKeyTimeoutMs, cfg.ShutdownTimeout.Milliseconds()))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if grpcServer != nil {
+		grpcAPI.Shutdown()
+		stopGRPC(shutdownCtx, grpcServer)
+	}
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := imports.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	logger.Info("сервер_остановлен")
+	return nil
+}
+
+// stopGRPC дожидается завершения текущих вызовов, а по истечении ctx
+// обрывает оставшиеся.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+func newAuthenticator(ctx context.Context, cfg *config.Config, logger *transport.Logger) (*auth.Authenticator, error) {
+	var keys auth.KeySource
+	if cfg.AuthJWKSURL != "" {
+		jwks := auth.NewJWKS(cfg.AuthJWKSURL, auth.WithRefreshInterval(cfg.AuthJWKSRefresh))
+		// Недоступный при старте JWKS не мешает запуску: ключи загрузятся при первом запросе.
+		if err := jwks.Refresh(ctx); err != nil {
+			logger.Warn("ошибка_загрузки_jwks", slog.String(transport.LogKeyError, err.Error()))
+		}
+		keys = jwks
+	} else {
+		var public interface{}
+		if cfg.AuthPublicKeyFile != "" {
+			data, err := os.ReadFile(cfg.AuthPublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("ошибка чтения AUTH_PUBLIC_KEY_FILE: %w", err)
+			}
+			if public, err = auth.ParsePublicKeyPEM(data); err != nil {
+				return nil, fmt.Errorf("ошибка разбора AUTH_PUBLIC_KEY_FILE: %w", err)
+			}
+		}
+		var secret []byte
+		if cfg.AuthHMACSecret != "" {
+			secret = []byte(cfg.AuthHMACSecret)
+		}
+		static, err := auth.NewStaticKeys(public, secret)
+		if err != nil {
+			return nil, err
+		}
+		keys = static
+	}
+
+	return auth.NewAuthenticator(keys, cfg.AuthIssuer, cfg.AuthAudience, auth.WithLeeway(cfg.AuthLeeway)), nil
+}
+
+func runIdempotencySweeper(ctx context.Context, repo *repository.IdempotencyRepository, interval time.Duration, logger *transport.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := repo.DeleteExpired(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("ошибка_очистки_ключей_идемпотентности", slog.String(transport.LogKeyError, err.Error()))
+			}
+			continue
+		}
+		if deleted > 0 {
+			logger.Info("ключи_идемпотентности_очищены", slog.Int64(transport.LogKeyDeleted, deleted))
+		}
+	}
+}
+
+// runPolicyReloader перечитывает файл политики при изменении. Невалидный файл
+// не применяется, продолжает действовать прежняя политика.
+func runPolicyReloader(ctx context.Context, policy *authz.PolicyFile, interval time.Duration, logger *transport.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := policy.Reload()
+		if err != nil {
+			logger.Error("ошибка_перечитывания_политики", slog.String(transport.LogKeyError, err.Error()))
+			continue
+		}
+		if reloaded {
+			logger.Info("политика_доступа_обновлена")
+		}
+	}
+}
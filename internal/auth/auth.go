@@ -0,0 +1,130 @@
+// Package auth проверяет JWT bearer токены (RS256, ES256, HS256) и передает
+// личность клиента дальше через контекст запроса.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("невалидный токен")
+	ErrKeyNotFound  = errors.New("ключ подписи не найден")
+)
+
+// SupportedAlgorithms — алгоритмы подписи, которые принимает Authenticator.
+var SupportedAlgorithms = []string{"RS256", "ES256", "HS256"}
+
+// Identity — аутентифицированный клиент: sub и все claims токена.
+// Scopes заполняется только для API ключей: ключ получает ровно эти действия
+// вместо прав по ролям.
+type Identity struct {
+	Subject string
+	Claims  map[string]interface{}
+	Scopes  []string
+}
+
+// StringClaim возвращает строковый claim или "", если его нет.
+func (i *Identity) StringClaim(name string) string {
+	value, _ := i.Claims[name].(string)
+	return value
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// KeySource возвращает ключ проверки подписи для kid и alg из заголовка токена.
+// Тип ключа должен соответствовать alg: *rsa.PublicKey для RS256,
+// *ecdsa.PublicKey для ES256, []byte для HS256.
+type KeySource interface {
+	Key(ctx context.Context, kid, alg string) (interface{}, error)
+}
+
+type Authenticator struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+type authenticatorOptions struct {
+	leeway time.Duration
+	now    func() time.Time
+}
+
+type Option func(*authenticatorOptions)
+
+// WithLeeway задает допустимое расхождение часов при проверке exp и nbf.
+func WithLeeway(leeway time.Duration) Option {
+	return func(o *authenticatorOptions) {
+		o.leeway = leeway
+	}
+}
+
+// WithClock подменяет текущее время, используется в тестах.
+func WithClock(now func() time.Time) Option {
+	return func(o *authenticatorOptions) {
+		o.now = now
+	}
+}
+
+// NewAuthenticator принимает только токены с указанными iss и aud и с exp.
+// nbf проверяется, если присутствует.
+func NewAuthenticator(keys KeySource, issuer, audience string, opts ...Option) *Authenticator {
+	options := authenticatorOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Authenticator{
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(SupportedAlgorithms),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(options.leeway),
+			jwt.WithTimeFunc(options.now),
+		),
+	}
+}
+
+// Authenticate проверяет подпись и claims токена. Все ошибки оборачивают ErrInvalidToken.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(ctx, kid, t.Method.Alg())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: нет sub", ErrInvalidToken)
+	}
+
+	return &Identity{Subject: subject, Claims: claims}, nil
+}
+
+// BearerToken извлекает токен из значения заголовка Authorization.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,290 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/auth/authtest"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator_JWKS(t *testing.T) {
+	server := authtest.NewServer(t)
+	authenticator := server.Authenticator()
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "RS256",
+			token: server.SignRS256(t, authtest.Claims("user-1", nil)),
+		},
+		{
+			name:  "ES256",
+			token: server.SignES256(t, authtest.Claims("user-1", nil)),
+		},
+		{
+			name:  "aud списком",
+			token: server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"aud": []string{"other", authtest.Audience}})),
+		},
+		{
+			name:    "истек",
+			token:   server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "еще не действует",
+			token:   server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"nbf": now.Add(time.Hour).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "без exp",
+			token:   server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"exp": nil})),
+			wantErr: true,
+		},
+		{
+			name:    "чужой iss",
+			token:   server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"iss": "https://evil.test"})),
+			wantErr: true,
+		},
+		{
+			name:    "чужой aud",
+			token:   server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"aud": "other"})),
+			wantErr: true,
+		},
+		{
+			name:    "без sub",
+			token:   server.SignRS256(t, authtest.Claims("", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "подпись другим ключом",
+			token:   authtest.NewServer(t).SignRS256(t, authtest.Claims("user-1", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "HS256 без секрета",
+			token:   authtest.SignHS256(t, []byte("secret"), authtest.Claims("user-1", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "alg none",
+			token:   unsignedToken(t, authtest.Claims("user-1", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "мусор",
+			token:   "not.a.token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(context.Background(), tt.token)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, auth.ErrInvalidToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", identity.Subject)
+			assert.Equal(t, authtest.Issuer, identity.StringClaim("iss"))
+		})
+	}
+}
+
+func TestAuthenticator_Leeway(t *testing.T) {
+	server := authtest.NewServer(t)
+	token := server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"exp": time.Now().Add(-10 * time.Second).Unix()}))
+
+	_, err := server.Authenticator().Authenticate(context.Background(), token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = server.Authenticator(auth.WithLeeway(30*time.Second)).Authenticate(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+func TestJWKS_CachesAndRefreshesOnUnknownKid(t *testing.T) {
+	server := authtest.NewServer(t)
+	jwks := auth.NewJWKS(server.URL, auth.WithMinRefreshInterval(0))
+	authenticator := auth.NewAuthenticator(jwks, authtest.Issuer, authtest.Audience)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := authenticator.Authenticate(ctx, server.SignRS256(t, authtest.Claims("user-1", nil)))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, server.Requests(), "ключи должны браться из кэша")
+
+	server.RotateRSA(t)
+	_, err := authenticator.Authenticate(ctx, server.SignRS256(t, authtest.Claims("user-1", nil)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.Requests(), "неизвестный kid должен обновить кэш")
+}
+
+func TestJWKS_LimitsRefreshOnUnknownKid(t *testing.T) {
+	server := authtest.NewServer(t)
+	jwks := auth.NewJWKS(server.URL)
+	ctx := context.Background()
+
+	require.NoError(t, jwks.Refresh(ctx))
+	for i := 0; i < 5; i++ {
+		_, err := jwks.Key(ctx, "unknown", "RS256")
+		assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+	}
+	assert.Equal(t, 1, server.Requests())
+}
+
+func TestJWKS_KeepsKeysWhenServerUnavailable(t *testing.T) {
+	server := authtest.NewServer(t)
+	jwks := auth.NewJWKS(server.URL, auth.WithRefreshInterval(0))
+	authenticator := auth.NewAuthenticator(jwks, authtest.Issuer, authtest.Audience)
+	token := server.SignES256(t, authtest.Claims("user-1", nil))
+
+	_, err := authenticator.Authenticate(context.Background(), token)
+	require.NoError(t, err)
+
+	server.Close()
+	_, err = authenticator.Authenticate(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+func TestJWKS_RefreshDoesNotBlockCachedKeys(t *testing.T) {
+	server := authtest.NewServer(t)
+
+	var blocking, aborted atomic.Bool
+	release := make(chan struct{})
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if blocking.Load() {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				aborted.Store(true)
+				return
+			}
+		}
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		io.Copy(w, resp.Body)
+	}))
+	defer proxy.Close()
+
+	jwks := auth.NewJWKS(proxy.URL, auth.WithRefreshInterval(0), auth.WithMinRefreshInterval(0))
+	authenticator := auth.NewAuthenticator(jwks, authtest.Issuer, authtest.Audience)
+	ctx := context.Background()
+	token := server.SignRS256(t, authtest.Claims("user-1", nil))
+
+	_, err := authenticator.Authenticate(ctx, token)
+	require.NoError(t, err)
+
+	// Кэш устарел, обновление зависло, но известный ключ выдается сразу.
+	blocking.Store(true)
+	done := make(chan error, 1)
+	go func() {
+		_, err := authenticator.Authenticate(ctx, token)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("проверка токена ждала обновления ключей")
+	}
+
+	// Запрос с новым kid ждет обновления; его отмена не прерывает загрузку.
+	server.RotateRSA(t)
+	rotated := server.SignRS256(t, authtest.Claims("user-1", nil))
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = authenticator.Authenticate(canceled, rotated)
+	assert.Error(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	_, err = authenticator.Authenticate(ctx, rotated)
+	require.NoError(t, err)
+	assert.False(t, aborted.Load(), "отмена запроса не должна прерывать общую загрузку")
+}
+
+func TestStaticKeys(t *testing.T) {
+	server := authtest.NewServer(t)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	publicKey := server.PublicKeyRSA()
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	parsed, err := auth.ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+
+	keys, err := auth.NewStaticKeys(parsed, secret)
+	require.NoError(t, err)
+	authenticator := auth.NewAuthenticator(keys, authtest.Issuer, authtest.Audience)
+	ctx := context.Background()
+
+	_, err = authenticator.Authenticate(ctx, server.SignRS256(t, authtest.Claims("user-1", nil)))
+	assert.NoError(t, err)
+
+	_, err = authenticator.Authenticate(ctx, authtest.SignHS256(t, secret, authtest.Claims("user-1", nil)))
+	assert.NoError(t, err)
+
+	_, err = authenticator.Authenticate(ctx, authtest.SignHS256(t, []byte("wrong"), authtest.Claims("user-1", nil)))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// Открытый ключ RSA, подставленный как секрет HS256, не должен подходить.
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	rsaOnly, err := auth.NewStaticKeys(parsed, nil)
+	require.NoError(t, err)
+	_, err = auth.NewAuthenticator(rsaOnly, authtest.Issuer, authtest.Audience).
+		Authenticate(ctx, authtest.SignHS256(t, pemKey, authtest.Claims("user-1", nil)))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = auth.NewStaticKeys(nil, nil)
+	assert.Error(t, err)
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"Bearer abc", "abc", true},
+		{"bearer abc", "abc", true},
+		{"Bearer ", "", false},
+		{"Basic abc", "", false},
+		{"abc", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			token, ok := auth.BearerToken(tt.header)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, token)
+		})
+	}
+}
+
+func unsignedToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	return token
+}
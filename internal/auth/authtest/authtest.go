@@ -0,0 +1,174 @@
+// Package authtest поднимает локальный JWKS сервер и выпускает токены для тестов.
+package authtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"employees-api/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	Issuer   = "https://auth.example.test"
+	Audience = "employees-api"
+)
+
+// Server — JWKS сервер с одним RSA и одним EC ключом.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	rsaKey   *rsa.PrivateKey
+	rsaKid   string
+	rotation int
+	ecKey    *ecdsa.PrivateKey
+	requests atomic.Int32
+}
+
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{ecKey: mustECKey(t)}
+	s.rsaKey, s.rsaKid = mustRSAKey(t), "rsa-0"
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveJWKS))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Requests возвращает число обращений к JWKS.
+func (s *Server) Requests() int {
+	return int(s.requests.Load())
+}
+
+// RotateRSA заменяет RSA ключ новым с новым kid.
+func (s *Server) RotateRSA(t testing.TB) {
+	key := mustRSAKey(t)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rotation++
+	s.rsaKey, s.rsaKid = key, fmt.Sprintf("rsa-%d", s.rotation)
+}
+
+// PublicKeyRSA возвращает текущий открытый RSA ключ, например для проверки PEM.
+func (s *Server) PublicKeyRSA() *rsa.PublicKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &s.rsaKey.PublicKey
+}
+
+// Authenticator проверяет токены по ключам этого сервера.
+func (s *Server) Authenticator(opts ...auth.Option) *auth.Authenticator {
+	return auth.NewAuthenticator(auth.NewJWKS(s.URL), Issuer, Audience, opts...)
+}
+
+func (s *Server) SignRS256(t testing.TB, claims jwt.MapClaims) string {
+	s.mu.Lock()
+	key, kid := s.rsaKey, s.rsaKid
+	s.mu.Unlock()
+	return sign(t, jwt.SigningMethodRS256, kid, key, claims)
+}
+
+func (s *Server) SignES256(t testing.TB, claims jwt.MapClaims) string {
+	return sign(t, jwt.SigningMethodES256, "ec-0", s.ecKey, claims)
+}
+
+func SignHS256(t testing.TB, secret []byte, claims jwt.MapClaims) string {
+	return sign(t, jwt.SigningMethodHS256, "", secret, claims)
+}
+
+// Claims возвращает валидные claims с заданным sub; extra дополняет или заменяет их.
+func Claims(subject string, extra jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": Issuer,
+		"aud": Audience,
+		"sub": subject,
+		"iat": now.Unix(),
+		"nbf": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func sign(t testing.TB, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("ошибка подписи токена: %v", err)
+	}
+	return signed
+}
+
+func (s *Server) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+
+	s.mu.Lock()
+	rsaKey, rsaKid := s.rsaKey, s.rsaKid
+	s.mu.Unlock()
+
+	keys := []map[string]string{
+		{
+			"kty": "RSA",
+			"kid": rsaKid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   encodeBigInt(rsaKey.N),
+			"e":   encodeBigInt(big.NewInt(int64(rsaKey.E))),
+		},
+		{
+			"kty": "EC",
+			"kid": "ec-0",
+			"use": "sig",
+			"alg": "ES256",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(s.ecKey.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(s.ecKey.Y.FillBytes(make([]byte, 32))),
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+}
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func mustRSAKey(t testing.TB) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("ошибка генерации RSA ключа: %v", err)
+	}
+	return key
+}
+
+func mustECKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ошибка генерации EC ключа: %v", err)
+	}
+	return key
+}
@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	maxJWKSBytes = 1 << 20
+	// jwksFetchTimeout ограничивает загрузку ключей независимо от запросов,
+	// которые ее дожидаются.
+	jwksFetchTimeout = 5 * time.Second
+)
+
+// JWKS загружает открытые ключи по URL и кэширует их на refreshInterval.
+// Токен с неизвестным kid вызывает внеплановое обновление, но не чаще
+// minRefreshInterval, чтобы выдуманные kid не нагружали сервер ключей.
+// Если обновление не удалось, используются ранее загруженные ключи.
+//
+// Загрузка идет в фоне, одна на все запросы: пока она выполняется, ключи
+// из кэша продолжают выдаваться, а ждут ее только запросы с ключом, которого
+// в кэше нет. Отмена такого запроса не прерывает загрузку для остальных.
+type JWKS struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	now                func() time.Time
+
+	mu         sync.Mutex
+	keys       map[string]jwk
+	fetchedAt  time.Time
+	refreshing chan struct{}
+	refreshErr error
+}
+
+type jwk struct {
+	alg string
+	key interface{}
+}
+
+type JWKSOption func(*JWKS)
+
+func WithHTTPClient(client *http.Client) JWKSOption {
+	return func(j *JWKS) {
+		j.client = client
+	}
+}
+
+// WithRefreshInterval задает время жизни кэша ключей. По умолчанию 15 минут.
+func WithRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.refreshInterval = interval
+	}
+}
+
+// WithMinRefreshInterval ограничивает частоту обновлений из-за неизвестного kid.
+// По умолчанию 30 секунд.
+func WithMinRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.minRefreshInterval = interval
+	}
+}
+
+func NewJWKS(url string, opts ...JWKSOption) *JWKS {
+	j := &JWKS{
+		url:                url,
+		client:             &http.Client{Timeout: 5 * time.Second},
+		refreshInterval:    15 * time.Minute,
+		minRefreshInterval: 30 * time.Second,
+		now:                time.Now,
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+func (j *JWKS) Key(ctx context.Context, kid, alg string) (interface{}, error) {
+	j.mu.Lock()
+	age := j.now().Sub(j.fetchedAt)
+	key, found := j.lookup(kid, alg)
+	var done <-chan struct{}
+	if j.keys == nil || age >= j.refreshInterval || (!found && age >= j.minRefreshInterval) {
+		done = j.startRefresh()
+	} else if !found {
+		// Ключ мог появиться в уже идущей загрузке.
+		done = j.refreshing
+	}
+	j.mu.Unlock()
+
+	// Устаревший кэш обновляется в фоне, найденный ключ выдается сразу.
+	if found {
+		return key, nil
+	}
+	if done == nil {
+		return nil, ErrKeyNotFound
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if key, found := j.lookup(kid, alg); found {
+		return key, nil
+	}
+	if j.refreshErr != nil {
+		return nil, j.refreshErr
+	}
+	return nil, ErrKeyNotFound
+}
+
+// Refresh загружает ключи заново, например при старте, чтобы первый запрос не ждал.
+func (j *JWKS) Refresh(ctx context.Context) error {
+	j.mu.Lock()
+	done := j.startRefresh()
+	j.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.refreshErr
+}
+
+// startRefresh запускает загрузку ключей, если она еще не идет, и возвращает
+// канал, закрываемый по ее завершении. Вызывается под j.mu.
+func (j *JWKS) startRefresh() <-chan struct{} {
+	if j.refreshing != nil {
+		return j.refreshing
+	}
+
+	// Время фиксируется до запроса: при недоступном сервере ключей повторная
+	// попытка будет не раньше minRefreshInterval.
+	j.fetchedAt = j.now()
+	done := make(chan struct{})
+	j.refreshing = done
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+		defer cancel()
+		keys, err := j.fetch(ctx)
+
+		j.mu.Lock()
+		if err == nil {
+			j.keys = keys
+		}
+		j.refreshErr = err
+		j.refreshing = nil
+		j.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// lookup ищет ключ по kid. Токен без kid подходит, только если для alg в
+// наборе ровно один ключ.
+func (j *JWKS) lookup(kid, alg string) (interface{}, bool) {
+	if kid != "" {
+		k, ok := j.keys[kid]
+		if !ok || (k.alg != "" && k.alg != alg) || !keyMatchesAlg(k.key, alg) {
+			return nil, false
+		}
+		return k.key, true
+	}
+
+	var match interface{}
+	for _, k := range j.keys {
+		if (k.alg == "" || k.alg == alg) && keyMatchesAlg(k.key, alg) {
+			if match != nil {
+				return nil, false
+			}
+			match = k.key
+		}
+	}
+	return match, match != nil
+}
+
+func (j *JWKS) fetch(ctx context.Context) (map[string]jwk, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки JWKS: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка загрузки JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ошибка загрузки JWKS: статус %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("ошибка разбора JWKS: %w", err)
+	}
+
+	keys := make(map[string]jwk, len(set.Keys))
+	for _, raw := range set.Keys {
+		kid, k, err := parseJWK(raw)
+		if err != nil {
+			// Ключи неподдерживаемых типов пропускаются, остальные остаются рабочими.
+			continue
+		}
+		keys[kid] = k
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("ошибка разбора JWKS: нет поддерживаемых ключей")
+	}
+
+	return keys, nil
+}
+
+func parseJWK(raw json.RawMessage) (string, jwk, error) {
+	var k struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	if err := json.Unmarshal(raw, &k); err != nil {
+		return "", jwk{}, err
+	}
+	if k.Use != "" && k.Use != "sig" {
+		return "", jwk{}, fmt.Errorf("ключ %q не для подписи", k.Kid)
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return "", jwk{}, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return "", jwk{}, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return "", jwk{}, errors.New("некорректная экспонента RSA")
+		}
+		return k.Kid, jwk{alg: k.Alg, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return "", jwk{}, fmt.Errorf("неподдерживаемая кривая %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return "", jwk{}, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return "", jwk{}, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return "", jwk{}, errors.New("точка не на кривой P-256")
+		}
+		return k.Kid, jwk{alg: k.Alg, key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+	default:
+		return "", jwk{}, fmt.Errorf("неподдерживаемый тип ключа %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("некорректное значение ключа")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// StaticKeys — ключи из конфигурации: открытый ключ RSA или EC из PEM и/или
+// общий секрет для HS256. kid не учитывается.
+type StaticKeys struct {
+	public interface{}
+	secret []byte
+}
+
+// NewStaticKeys принимает *rsa.PublicKey, *ecdsa.PublicKey (P-256) или nil и секрет HS256 или nil.
+func NewStaticKeys(public interface{}, secret []byte) (*StaticKeys, error) {
+	if public == nil && len(secret) == 0 {
+		return nil, errors.New("не задан ни открытый ключ, ни секрет")
+	}
+	switch key := public.(type) {
+	case nil, *rsa.PublicKey:
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("для ES256 нужен ключ на кривой P-256")
+		}
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип ключа %T", public)
+	}
+	return &StaticKeys{public: public, secret: secret}, nil
+}
+
+func (s *StaticKeys) Key(_ context.Context, _ string, alg string) (interface{}, error) {
+	if alg == "HS256" {
+		if len(s.secret) == 0 {
+			return nil, ErrKeyNotFound
+		}
+		return s.secret, nil
+	}
+	if !keyMatchesAlg(s.public, alg) {
+		return nil, ErrKeyNotFound
+	}
+	return s.public, nil
+}
+
+// ParsePublicKeyPEM читает открытый ключ из блока PUBLIC KEY, RSA PUBLIC KEY или CERTIFICATE.
+func ParsePublicKeyPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("не найден PEM блок")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("неподдерживаемый PEM блок %q", block.Type)
+	}
+}
+
+// keyMatchesAlg не дает проверить токен ключом другого типа, например
+// подставить открытый RSA ключ как секрет HS256.
+func keyMatchesAlg(key interface{}, alg string) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return alg == "RS256"
+	case *ecdsa.PublicKey:
+		return alg == "ES256" && k.Curve == elliptic.P256()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+package authz
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PolicyFile держит политику из файла и перечитывает ее при изменении, чтобы
+// права можно было поменять без перезапуска сервиса.
+type PolicyFile struct {
+	path   string
+	policy atomic.Pointer[Policy]
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// LoadPolicyFile читает политику; ошибка при старте фатальна, в отличие от Reload.
+func LoadPolicyFile(path string) (*PolicyFile, error) {
+	f := &PolicyFile{path: path}
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Policy возвращает текущую политику.
+func (f *PolicyFile) Policy() *Policy {
+	return f.policy.Load()
+}
+
+// Reload перечитывает файл, если изменились время модификации или размер.
+// При ошибке остается прежняя политика.
+func (f *PolicyFile) Reload() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("ошибка чтения политики: %w", err)
+	}
+	if f.policy.Load() != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("ошибка чтения политики: %w", err)
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return false, err
+	}
+
+	f.policy.Store(policy)
+	f.modTime, f.size = info.ModTime(), info.Size()
+	return true, nil
+}
@@ -0,0 +1,200 @@
+// Package authz проверяет права клиента на операции с сотрудниками по
+// ролям из JWT и атрибутам ресурса. Политика задается JSON файлом.
+package authz
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"employees-api/internal/auth"
+)
+
+var ErrForbidden = errors.New("недостаточно прав")
+
+type Action string
+
+const (
+	ActionCreate  Action = "employees.create"
+	ActionRead    Action = "employees.read"
+	ActionUpdate  Action = "employees.update"
+	ActionDelete  Action = "employees.delete"
+	ActionRestore Action = "employees.restore"
+	ActionPurge   Action = "employees.purge"
+	ActionList    Action = "employees.list"
+	ActionSearch  Action = "employees.search"
+	ActionExport  Action = "employees.export"
+
+	ActionImportCreate Action = "imports.create"
+	ActionImportRead   Action = "imports.read"
+
+	ActionAPIKeyCreate Action = "apikeys.create"
+	ActionAPIKeyRead   Action = "apikeys.read"
+	ActionAPIKeyRotate Action = "apikeys.rotate"
+	ActionAPIKeyRevoke Action = "apikeys.revoke"
+)
+
+var knownActions = map[Action]bool{
+	ActionCreate: true, ActionRead: true, ActionUpdate: true, ActionDelete: true,
+	ActionRestore: true, ActionPurge: true, ActionList: true, ActionSearch: true,
+	ActionExport: true, ActionImportCreate: true, ActionImportRead: true,
+	ActionAPIKeyCreate: true, ActionAPIKeyRead: true, ActionAPIKeyRotate: true, ActionAPIKeyRevoke: true,
+}
+
+// Resource — атрибуты сотрудника, доступные в условиях правил. Для операций
+// над коллекцией (list, search, export) и импортом атрибуты пустые, поэтому
+// правила с условиями к ним не применяются.
+type Resource struct {
+	ID   string
+	City string
+}
+
+func (r Resource) attribute(name string) string {
+	switch name {
+	case "id":
+		return r.ID
+	case "city":
+		return r.City
+	}
+	return ""
+}
+
+var knownAttributes = map[string]bool{"id": true, "city": true}
+
+const claimRefPrefix = "$claims."
+
+// Policy сопоставляет роли с правилами. Роли берутся из claim RoleClaim
+// (строка или массив строк). Доступ разрешен, если подходит хотя бы одно
+// правило любой из ролей клиента.
+type Policy struct {
+	RoleClaim string            `json:"roleClaim"`
+	Roles     map[string][]Rule `json:"roles"`
+}
+
+// Rule разрешает Actions ("employees.update", "employees.*" или "*"), если
+// выполнены все условия When. Ключ условия — атрибут ресурса (id, city),
+// значение — строка или ссылка на claim токена вида "$claims.city".
+type Rule struct {
+	Actions []string          `json:"actions"`
+	When    map[string]string `json:"when,omitempty"`
+}
+
+// ParsePolicy разбирает и проверяет политику, неизвестные поля, действия и
+// атрибуты считаются ошибкой, чтобы опечатка не открыла или не закрыла доступ молча.
+func ParsePolicy(data []byte) (*Policy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var p Policy
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("невалидная политика: %w", err)
+	}
+	if p.RoleClaim == "" {
+		p.RoleClaim = "role"
+	}
+	if len(p.Roles) == 0 {
+		return nil, errors.New("невалидная политика: нет ролей")
+	}
+
+	for role, rules := range p.Roles {
+		for i, rule := range rules {
+			if len(rule.Actions) == 0 {
+				return nil, fmt.Errorf("невалидная политика: роль %q, правило %d без actions", role, i)
+			}
+			for _, action := range rule.Actions {
+				if !ValidAction(action) {
+					return nil, fmt.Errorf("невалидная политика: роль %q, неизвестное действие %q", role, action)
+				}
+			}
+			for attr := range rule.When {
+				if !knownAttributes[attr] {
+					return nil, fmt.Errorf("невалидная политика: роль %q, неизвестный атрибут %q", role, attr)
+				}
+			}
+		}
+	}
+
+	return &p, nil
+}
+
+// ValidAction сообщает, что pattern — известное действие, группа известных
+// действий вида "employees.*" или "*".
+func ValidAction(pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, ".*"); ok {
+		for action := range knownActions {
+			if strings.HasPrefix(string(action), prefix+".") {
+				return true
+			}
+		}
+		return false
+	}
+	return knownActions[Action(pattern)]
+}
+
+// Allowed сообщает, может ли identity выполнить action над resource.
+// API ключу (identity.Scopes != nil) роли не назначаются, он получает
+// действия из Scopes без условий на ресурс.
+func (p *Policy) Allowed(identity *auth.Identity, action Action, resource Resource) bool {
+	if identity == nil {
+		return false
+	}
+	if identity.Scopes != nil {
+		return Rule{Actions: identity.Scopes}.matchesAction(action)
+	}
+	for _, role := range p.roles(identity) {
+		for _, rule := range p.Roles[role] {
+			if rule.matchesAction(action) && rule.matchesResource(identity, resource) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Policy) roles(identity *auth.Identity) []string {
+	switch v := identity.Claims[p.RoleClaim].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		roles := make([]string, 0, len(v))
+		for _, item := range v {
+			if role, ok := item.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+		return roles
+	}
+	return nil
+}
+
+func (r Rule) matchesAction(action Action) bool {
+	for _, pattern := range r.Actions {
+		if pattern == "*" || pattern == string(action) {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(string(action), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchesResource(identity *auth.Identity, resource Resource) bool {
+	for attr, expected := range r.When {
+		if claim, ok := strings.CutPrefix(expected, claimRefPrefix); ok {
+			expected = identity.StringClaim(claim)
+		}
+		actual := resource.attribute(attr)
+		// Пустое значение не совпадает ни с чем: иначе токен без claim city
+		// получил бы доступ к ресурсам без атрибута.
+		if expected == "" || actual == "" || actual != expected {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package authz
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"employees-api/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `{
+  "roleClaim": "role",
+  "roles": {
+    "hr_admin": [{"actions": ["employees.*", "imports.*"]}],
+    "manager": [
+      {"actions": ["employees.read", "employees.list", "employees.search", "employees.export"]},
+      {"actions": ["employees.update"], "when": {"city": "$claims.city"}}
+    ],
+    "employee": [{"actions": ["employees.read"], "when": {"id": "$claims.employee_id"}}]
+  }
+}`
+
+func identity(role interface{}, claims map[string]interface{}) *auth.Identity {
+	all := map[string]interface{}{"sub": "user", "role": role}
+	for k, v := range claims {
+		all[k] = v
+	}
+	return &auth.Identity{Subject: "user", Claims: all}
+}
+
+func apiKey(scopes ...string) *auth.Identity {
+	return &auth.Identity{Subject: "apikey:1", Claims: map[string]interface{}{}, Scopes: scopes}
+}
+
+func TestPolicy_Allowed(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	almaty := Resource{ID: "e-1", City: "Алматы"}
+	astana := Resource{ID: "e-2", City: "Астана"}
+	admin := identity("hr_admin", nil)
+	manager := identity("manager", map[string]interface{}{"city": "Алматы"})
+	employee := identity("employee", map[string]interface{}{"employee_id": "e-1"})
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		action   Action
+		resource Resource
+		want     bool
+	}{
+		{"админ создает", admin, ActionCreate, astana, true},
+		{"админ удаляет", admin, ActionDelete, astana, true},
+		{"админ окончательно удаляет", admin, ActionPurge, Resource{ID: "e-2"}, true},
+		{"админ импортирует", admin, ActionImportCreate, Resource{}, true},
+		{"менеджер читает чужой город", manager, ActionRead, astana, true},
+		{"менеджер выгружает", manager, ActionExport, Resource{}, true},
+		{"менеджер обновляет свой город", manager, ActionUpdate, almaty, true},
+		{"менеджер обновляет чужой город", manager, ActionUpdate, astana, false},
+		{"менеджер создает", manager, ActionCreate, almaty, false},
+		{"менеджер удаляет", manager, ActionDelete, almaty, false},
+		{"сотрудник читает себя", employee, ActionRead, almaty, true},
+		{"сотрудник читает другого", employee, ActionRead, astana, false},
+		{"сотрудник получает список", employee, ActionList, Resource{}, false},
+		{"сотрудник обновляет себя", employee, ActionUpdate, almaty, false},
+		{"менеджер без claim city", identity("manager", nil), ActionUpdate, Resource{ID: "e-3"}, false},
+		{"несколько ролей", identity([]interface{}{"employee", "hr_admin"}, nil), ActionDelete, astana, true},
+		{"неизвестная роль", identity("guest", nil), ActionRead, almaty, false},
+		{"без роли", &auth.Identity{Subject: "user", Claims: map[string]interface{}{}}, ActionRead, almaty, false},
+		{"без аутентификации", nil, ActionRead, almaty, false},
+		{"API ключ с действием", apiKey("employees.read"), ActionRead, astana, true},
+		{"API ключ с группой действий", apiKey("imports.*"), ActionImportCreate, Resource{}, true},
+		{"API ключ без действия", apiKey("employees.read"), ActionUpdate, almaty, false},
+		{"API ключ с ролью в claims", &auth.Identity{Subject: "apikey:1", Claims: map[string]interface{}{"role": "hr_admin"}, Scopes: []string{}}, ActionCreate, almaty, false},
+		{"админ без прав на ключи", admin, ActionAPIKeyCreate, Resource{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Allowed(tt.identity, tt.action, tt.resource))
+		})
+	}
+}
+
+func TestParsePolicy_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{"невалидный JSON", `{`},
+		{"нет ролей", `{"roles": {}}`},
+		{"неизвестное поле", `{"roles": {"a": [{"actions": ["*"], "if": {}}]}}`},
+		{"неизвестное действие", `{"roles": {"a": [{"actions": ["employees.fire"]}]}}`},
+		{"неизвестная группа действий", `{"roles": {"a": [{"actions": ["payroll.*"]}]}}`},
+		{"неизвестный атрибут", `{"roles": {"a": [{"actions": ["employees.read"], "when": {"phone": "x"}}]}}`},
+		{"правило без действий", `{"roles": {"a": [{"when": {"city": "x"}}]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.policy))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPolicyFile_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+
+	file, err := LoadPolicyFile(path)
+	require.NoError(t, err)
+	manager := identity("manager", map[string]interface{}{"city": "Алматы"})
+	assert.False(t, file.Policy().Allowed(manager, ActionCreate, Resource{City: "Алматы"}))
+
+	reloaded, err := file.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "неизмененный файл не перечитывается")
+
+	updated := `{"roles": {"manager": [{"actions": ["employees.*"]}]}}`
+	require.NoError(t, os.WriteFile(path, []byte(updated), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+	reloaded, err = file.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.True(t, file.Policy().Allowed(manager, ActionCreate, Resource{City: "Алматы"}))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"roles": `), 0o600))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+
+	_, err = file.Reload()
+	assert.Error(t, err)
+	assert.True(t, file.Policy().Allowed(manager, ActionCreate, Resource{City: "Алматы"}), "невалидный файл не заменяет политику")
+}
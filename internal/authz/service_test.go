@@ -0,0 +1,176 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployeeService_ManagerUpdatesOnlyOwnCity(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	authorized := NewEmployeeService(svc, Static(policy))
+
+	ctx := context.Background()
+	almaty, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"})
+	require.NoError(t, err)
+	astana, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Петр Петров", Phone: "+77010000002", City: "Астана"})
+	require.NoError(t, err)
+
+	managerCtx := auth.WithIdentity(ctx, identity("manager", map[string]interface{}{"city": "Алматы"}))
+
+	_, err = authorized.PatchEmployee(managerCtx, almaty.ID, 0, domain.EmployeePatch{FullName: strPtr("Иван Петров")})
+	assert.NoError(t, err)
+
+	_, err = authorized.PatchEmployee(managerCtx, astana.ID, 0, domain.EmployeePatch{FullName: strPtr("Петр Иванов")})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = authorized.PatchEmployee(managerCtx, almaty.ID, 0, domain.EmployeePatch{City: strPtr("Астана")})
+	assert.ErrorIs(t, err, ErrForbidden, "нельзя перевести сотрудника в чужой город")
+
+	_, err = authorized.UpdateEmployee(managerCtx, almaty.ID, 0, domain.UpdateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: " Алматы "})
+	assert.NoError(t, err)
+
+	_, err = authorized.CreateEmployee(managerCtx, domain.CreateEmployeeRequest{FullName: "Анна Смирнова", Phone: "+77010000003", City: "Алматы"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	assert.ErrorIs(t, authorized.DeleteEmployee(managerCtx, almaty.ID, 0), ErrForbidden)
+
+	_, err = authorized.ListEmployees(managerCtx, domain.ListEmployeesRequest{Limit: 10})
+	assert.NoError(t, err)
+
+	_, err = authorized.GetEmployeeByID(managerCtx, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestEmployeeService_EmployeeReadsOnlyOwnRecord(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	authorized := NewEmployeeService(svc, Static(policy))
+
+	ctx := context.Background()
+	self, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"})
+	require.NoError(t, err)
+	other, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Петр Петров", Phone: "+77010000002", City: "Алматы"})
+	require.NoError(t, err)
+
+	employeeCtx := auth.WithIdentity(ctx, identity("employee", map[string]interface{}{"employee_id": self.ID.String()}))
+
+	got, err := authorized.GetEmployeeByID(employeeCtx, self.ID)
+	require.NoError(t, err)
+	assert.Equal(t, self.ID, got.ID)
+
+	_, err = authorized.GetEmployeeByID(employeeCtx, other.ID)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	found, err := authorized.GetEmployeesByIDs(employeeCtx, []uuid.UUID{self.ID})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	_, err = authorized.GetEmployeesByIDs(employeeCtx, []uuid.UUID{self.ID, other.ID})
+	assert.ErrorIs(t, err, ErrForbidden, "чужой сотрудник в пакете запрещает весь пакет")
+
+	found, err = authorized.GetEmployeesByIDs(employeeCtx, []uuid.UUID{uuid.New()})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	_, err = authorized.SearchEmployees(employeeCtx, domain.SearchEmployeesRequest{Query: "Петр"})
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	_, err = authorized.GetEmployeeByID(ctx, self.ID)
+	assert.ErrorIs(t, err, ErrForbidden, "без аутентификации доступа нет")
+
+	assert.NoError(t, authorized.HealthCheck(ctx))
+}
+
+// movingEmployees переводит сотрудника в другой город между проверкой прав и
+// записью, как конкурентный запрос.
+type movingEmployees struct {
+	service.Employees
+	moved bool
+}
+
+func (s *movingEmployees) GetEmployeeByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	emp, err := s.Employees.GetEmployeeByID(ctx, id)
+	if err != nil || s.moved {
+		return emp, err
+	}
+	s.moved = true
+	if _, err := s.Employees.PatchEmployee(ctx, id, emp.Version, domain.EmployeePatch{City: strPtr("Астана")}); err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+func TestEmployeeService_WriteWithoutVersionAppliesToAuthorizedState(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	managerCtx := auth.WithIdentity(ctx, identity("manager", map[string]interface{}{"city": "Алматы"}))
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+
+	tests := []struct {
+		name  string
+		phone string
+		write func(s *EmployeeService, id uuid.UUID) error
+	}{
+		{"update", "+77010000001", func(s *EmployeeService, id uuid.UUID) error {
+			_, err := s.UpdateEmployee(managerCtx, id, 0, domain.UpdateEmployeeRequest{FullName: "Иван Петров", Phone: "+77010000001", City: "Алматы"})
+			return err
+		}},
+		{"patch", "+77010000002", func(s *EmployeeService, id uuid.UUID) error {
+			_, err := s.PatchEmployee(managerCtx, id, 0, domain.EmployeePatch{FullName: strPtr("Иван Петров")})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emp, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: tt.phone, City: "Алматы"})
+			require.NoError(t, err)
+
+			err = tt.write(NewEmployeeService(&movingEmployees{Employees: svc}, Static(policy)), emp.ID)
+			assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+			got, err := svc.GetEmployeeByID(ctx, emp.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Иван Иванов", got.FullName, "запись в чужом городе не применилась")
+		})
+	}
+}
+
+func TestAPIKeyService_RejectsUnknownScopes(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{"roles": {"admin": [{"actions": ["apikeys.*"]}]}}`))
+	require.NoError(t, err)
+
+	svc := NewAPIKeyService(service.NewAPIKeyService(repository.NewMemoryAPIKeyStore()), Static(policy))
+	ctx := auth.WithIdentity(context.Background(), identity("admin", nil))
+
+	for _, scopes := range [][]string{{"employees.raed"}, {"employees.read", "imports.delete"}, {"payroll.*"}} {
+		_, err := svc.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{Name: "payroll", Scopes: scopes})
+		var validationErrs *service.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs, scopes)
+		assert.Equal(t, "scopes", validationErrs.Errors[0].Field)
+	}
+
+	issued, err := svc.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{Name: "payroll", Scopes: []string{"employees.read", "imports.*"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"employees.read", "imports.*"}, issued.Scopes)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -2,21 +2,65 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/service"
 )
 
 type Config struct {
-	Port             string
-	PostgresDSN      string
-	DBMaxConns       int32
-	DBMinConns       int32
-	DBMaxConnLifetime time.Duration
+	Port                string
+	PostgresDSN         string
+	DBMaxConns          int32
+	DBMinConns          int32
+	DBMaxConnLifetime   time.Duration
 	DBHealthCheckPeriod time.Duration
-	ReadTimeout      time.Duration
-	WriteTimeout     time.Duration
-	RunMigrations    bool
+	ReadTimeout         time.Duration
+	WriteTimeout        time.Duration
+	RunMigrations       bool
+	MigrationsDir       string
+	ShutdownTimeout     time.Duration
+	SearchThreshold     float64
+	IdempotencyTTL      time.Duration
+	IdempotencySweep    time.Duration
+	ImportJobTimeout    time.Duration
+	ImportMaxBytes      int64
+	ExportTimeout       time.Duration
+	TracingEnabled      bool
+	TracingSampleRatio  float64
+	OTLPEndpoint        string
+	OTLPInsecure        bool
+	LogLevel            slog.Level
+	LogFormat           string
+	LogFieldLanguage    string
+	LogClearFields      []string
+	AuthEnabled         bool
+	AuthIssuer          string
+	AuthAudience        string
+	AuthJWKSURL         string
+	AuthJWKSRefresh     time.Duration
+	AuthPublicKeyFile   string
+	AuthHMACSecret      string
+	AuthLeeway          time.Duration
+	AuthExemptPaths     []string
+	AuthzPolicyFile     string
+	AuthzReload         time.Duration
+	APIKeysEnabled      bool
+	RateLimitEnabled    bool
+	RateLimitDefault    ratelimit.Limit
+	RateLimitPerIP      ratelimit.Limit
+	RateLimitRules      []ratelimit.Rule
+	TrustedProxies      []netip.Prefix
+	ErrorFormat         string
+	GRPCEnabled         bool
+	GRPCPort            string
+	GraphQLEnabled      bool
+	GraphQLMaxCost      int
 }
 
 func Load() (*Config, error) {
@@ -34,6 +78,129 @@ func Load() (*Config, error) {
 	readTimeout := getEnvAsDuration("READ_TIMEOUT_MS", 5000)
 	writeTimeout := getEnvAsDuration("WRITE_TIMEOUT_MS", 10000)
 	runMigrations := getEnvAsBool("RUN_MIGRATIONS", true)
+	migrationsDir := getEnvOrDefault("MIGRATIONS_DIR", "migrations")
+	shutdownTimeout := getEnvAsDuration("SHUTDOWN_TIMEOUT_MS", 15000)
+	searchThreshold := getEnvAsFloat64("SEARCH_SIMILARITY_THRESHOLD", 0.3)
+	if err := service.ValidateSearchThreshold(searchThreshold); err != nil {
+		return nil, fmt.Errorf("SEARCH_SIMILARITY_THRESHOLD: %w", err)
+	}
+	idempotencyTTL := getEnvAsDuration("IDEMPOTENCY_TTL_MS", 24*60*60*1000)
+	idempotencySweep := getEnvAsDuration("IDEMPOTENCY_SWEEP_INTERVAL_MS", 10*60*1000)
+	if idempotencySweep <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_SWEEP_INTERVAL_MS должен быть больше нуля")
+	}
+	importJobTimeout := getEnvAsDuration("IMPORT_JOB_TIMEOUT_MS", 30*60*1000)
+	importMaxBytes := getEnvAsInt64("IMPORT_MAX_BYTES", 20*1024*1024)
+	exportTimeout := getEnvAsDuration("EXPORT_TIMEOUT_MS", 10*60*1000)
+	tracingEnabled := getEnvAsBool("TRACING_ENABLED", false)
+	tracingSampleRatio := getEnvAsFloat64("TRACING_SAMPLE_RATIO", 1.0)
+	otlpEndpoint := getEnvOrDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4318")
+	otlpInsecure := getEnvAsBool("OTEL_EXPORTER_OTLP_INSECURE", true)
+
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(getEnvOrDefault("LOG_LEVEL", "info"))); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL должен быть debug, info, warn или error")
+	}
+
+	logFormat := getEnvOrDefault("LOG_FORMAT", "json")
+	if logFormat != "json" && logFormat != "text" {
+		return nil, fmt.Errorf("LOG_FORMAT должен быть json или text")
+	}
+
+	logFieldLanguage := getEnvOrDefault("LOG_FIELD_LANGUAGE", "ru")
+	if logFieldLanguage != "ru" && logFieldLanguage != "en" {
+		return nil, fmt.Errorf("LOG_FIELD_LANGUAGE должен быть ru или en")
+	}
+
+	logClearFields := getEnvAsList("LOG_CLEAR_FIELDS")
+
+	errorFormat := getEnvOrDefault("ERROR_FORMAT", "json")
+	if errorFormat != "json" && errorFormat != "problem" {
+		return nil, fmt.Errorf("ERROR_FORMAT должен быть json или problem")
+	}
+
+	grpcEnabled := getEnvAsBool("GRPC_ENABLED", false)
+	grpcPort := getEnvOrDefault("GRPC_PORT", "9090")
+	if grpcEnabled && grpcPort == port {
+		return nil, fmt.Errorf("GRPC_PORT должен отличаться от PORT")
+	}
+
+	graphQLEnabled := getEnvAsBool("GRAPHQL_ENABLED", false)
+	graphQLMaxCost := getEnvAsInt64("GRAPHQL_MAX_COST", 1000)
+	if graphQLMaxCost <= 0 {
+		return nil, fmt.Errorf("GRAPHQL_MAX_COST должен быть больше нуля")
+	}
+
+	authEnabled := getEnvAsBool("AUTH_ENABLED", false)
+	authIssuer := os.Getenv("AUTH_ISSUER")
+	authAudience := os.Getenv("AUTH_AUDIENCE")
+	authJWKSURL := os.Getenv("AUTH_JWKS_URL")
+	authJWKSRefresh := getEnvAsDuration("AUTH_JWKS_REFRESH_MS", 15*60*1000)
+	authPublicKeyFile := os.Getenv("AUTH_PUBLIC_KEY_FILE")
+	authHMACSecret := os.Getenv("AUTH_HMAC_SECRET")
+	authLeeway := getEnvAsDuration("AUTH_LEEWAY_MS", 30*1000)
+	authExemptPaths := getEnvAsList("AUTH_EXEMPT_PATHS")
+	if authExemptPaths == nil {
+		authExemptPaths = []string{"/v1/healthz", "/v1/openapi.json"}
+	}
+
+	authzPolicyFile := os.Getenv("AUTHZ_POLICY_FILE")
+	authzReload := getEnvAsDuration("AUTHZ_RELOAD_INTERVAL_MS", 10*1000)
+	if authzReload <= 0 {
+		return nil, fmt.Errorf("AUTHZ_RELOAD_INTERVAL_MS должен быть больше нуля")
+	}
+	if authzPolicyFile != "" && !authEnabled {
+		return nil, fmt.Errorf("AUTHZ_POLICY_FILE требует AUTH_ENABLED=true")
+	}
+
+	// Права API ключа проверяет authz, без политики ключ получил бы полный доступ.
+	apiKeysEnabled := getEnvAsBool("API_KEYS_ENABLED", false)
+	if apiKeysEnabled && authzPolicyFile == "" {
+		return nil, fmt.Errorf("API_KEYS_ENABLED требует AUTHZ_POLICY_FILE")
+	}
+
+	if authEnabled {
+		if authIssuer == "" || authAudience == "" {
+			return nil, fmt.Errorf("AUTH_ISSUER и AUTH_AUDIENCE обязательны при AUTH_ENABLED=true")
+		}
+		if authJWKSURL == "" && authPublicKeyFile == "" && authHMACSecret == "" {
+			return nil, fmt.Errorf("при AUTH_ENABLED=true нужен AUTH_JWKS_URL, AUTH_PUBLIC_KEY_FILE или AUTH_HMAC_SECRET")
+		}
+		if authJWKSURL != "" && (authPublicKeyFile != "" || authHMACSecret != "") {
+			return nil, fmt.Errorf("AUTH_JWKS_URL нельзя сочетать с AUTH_PUBLIC_KEY_FILE и AUTH_HMAC_SECRET")
+		}
+	}
+
+	rateLimitEnabled := getEnvAsBool("RATE_LIMIT_ENABLED", false)
+	rateLimitDefault, err := ratelimit.ParseLimit(getEnvOrDefault("RATE_LIMIT_DEFAULT", "10/s:20"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+	rateLimitPerIP, err := ratelimit.ParseLimit(getEnvOrDefault("RATE_LIMIT_PER_IP", "50/s:100"))
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_PER_IP: %w", err)
+	}
+	rateLimitRuleSpecs := getEnvAsList("RATE_LIMIT_RULES")
+	if rateLimitRuleSpecs == nil {
+		rateLimitRuleSpecs = []string{"* /v1/healthz=off", "* /metrics=off"}
+	}
+	rateLimitRules := make([]ratelimit.Rule, 0, len(rateLimitRuleSpecs))
+	for _, spec := range rateLimitRuleSpecs {
+		rule, err := ratelimit.ParseRule(spec)
+		if err != nil {
+			return nil, fmt.Errorf("RATE_LIMIT_RULES: %w", err)
+		}
+		rateLimitRules = append(rateLimitRules, rule)
+	}
+
+	var trustedProxies []netip.Prefix
+	for _, value := range getEnvAsList("TRUSTED_PROXIES") {
+		prefix, err := parsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES: %w", err)
+		}
+		trustedProxies = append(trustedProxies, prefix)
+	}
 
 	return &Config{
 		Port:                port,
@@ -45,6 +212,44 @@ func Load() (*Config, error) {
 		ReadTimeout:         readTimeout,
 		WriteTimeout:        writeTimeout,
 		RunMigrations:       runMigrations,
+		MigrationsDir:       migrationsDir,
+		ShutdownTimeout:     shutdownTimeout,
+		SearchThreshold:     searchThreshold,
+		IdempotencyTTL:      idempotencyTTL,
+		IdempotencySweep:    idempotencySweep,
+		ImportJobTimeout:    importJobTimeout,
+		ImportMaxBytes:      importMaxBytes,
+		ExportTimeout:       exportTimeout,
+		TracingEnabled:      tracingEnabled,
+		TracingSampleRatio:  tracingSampleRatio,
+		OTLPEndpoint:        otlpEndpoint,
+		OTLPInsecure:        otlpInsecure,
+		LogLevel:            logLevel,
+		LogFormat:           logFormat,
+		LogFieldLanguage:    logFieldLanguage,
+		LogClearFields:      logClearFields,
+		AuthEnabled:         authEnabled,
+		AuthIssuer:          authIssuer,
+		AuthAudience:        authAudience,
+		AuthJWKSURL:         authJWKSURL,
+		AuthJWKSRefresh:     authJWKSRefresh,
+		AuthPublicKeyFile:   authPublicKeyFile,
+		AuthHMACSecret:      authHMACSecret,
+		AuthLeeway:          authLeeway,
+		AuthExemptPaths:     authExemptPaths,
+		AuthzPolicyFile:     authzPolicyFile,
+		AuthzReload:         authzReload,
+		APIKeysEnabled:      apiKeysEnabled,
+		RateLimitEnabled:    rateLimitEnabled,
+		RateLimitDefault:    rateLimitDefault,
+		RateLimitPerIP:      rateLimitPerIP,
+		RateLimitRules:      rateLimitRules,
+		TrustedProxies:      trustedProxies,
+		ErrorFormat:         errorFormat,
+		GRPCEnabled:         grpcEnabled,
+		GRPCPort:            grpcPort,
+		GraphQLEnabled:      graphQLEnabled,
+		GraphQLMaxCost:      int(graphQLMaxCost),
 	}, nil
 }
 
@@ -67,6 +272,30 @@ func getEnvAsInt32(key string, defaultValue int32) int32 {
 	return int32(value)
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvAsFloat64(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func getEnvAsDuration(key string, defaultMs int64) time.Duration {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -90,3 +319,31 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return value
 }
+
+// getEnvAsList разбирает список через запятую. Для незаданной переменной
+// возвращает nil, чтобы можно было отличить его от явно пустого списка.
+func getEnvAsList(key string) []string {
+	valueStr, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	values := []string{}
+	for _, value := range strings.Split(valueStr, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// parsePrefix принимает подсеть в нотации CIDR или отдельный адрес.
+func parsePrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		return netip.ParsePrefix(value)
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -11,7 +11,25 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+type poolOptions struct {
+	observer QueryObserver
+}
+
+type PoolOption func(*poolOptions)
+
+// WithQueryObserver передает длительность каждого SQL запроса наблюдателю.
+func WithQueryObserver(observer QueryObserver) PoolOption {
+	return func(o *poolOptions) {
+		o.observer = observer
+	}
+}
+
+func NewPool(ctx context.Context, cfg *config.Config, opts ...PoolOption) (*pgxpool.Pool, error) {
+	var options poolOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	poolConfig, err := pgxpool.ParseConfig(cfg.PostgresDSN)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка парсинга DSN: %w", err)
@@ -23,6 +41,7 @@ func NewPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
 	poolConfig.HealthCheckPeriod = cfg.DBHealthCheckPeriod
 
 	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	poolConfig.ConnConfig.Tracer = NewQueryTracer(options.observer)
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
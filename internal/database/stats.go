@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type queryStatsKey struct{}
+
+// QueryStats накапливает время всех SQL запросов одного HTTP запроса.
+// Заполняется QueryTracer, поэтому репозиториям не нужно замерять время вручную.
+type QueryStats struct {
+	mu          sync.Mutex
+	count       int
+	total       time.Duration
+	slowest     time.Duration
+	slowestName string
+}
+
+// QueryStatsSnapshot - значения QueryStats на момент вызова Snapshot.
+type QueryStatsSnapshot struct {
+	Count       int
+	Total       time.Duration
+	Slowest     time.Duration
+	SlowestName string
+}
+
+// WithQueryStats добавляет в контекст пустой счетчик запросов.
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{}
+	return context.WithValue(ctx, queryStatsKey{}, stats), stats
+}
+
+func queryStatsFromContext(ctx context.Context) *QueryStats {
+	stats, _ := ctx.Value(queryStatsKey{}).(*QueryStats)
+	return stats
+}
+
+// record учитывает queries запросов, выполненных за duration. Пакет считается
+// одним обращением к БД с числом запросов в нем.
+func (s *QueryStats) record(name string, duration time.Duration, queries int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count += queries
+	s.total += duration
+	if duration > s.slowest || s.slowestName == "" {
+		s.slowest = duration
+		s.slowestName = name
+	}
+}
+
+func (s *QueryStats) Snapshot() QueryStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return QueryStatsSnapshot{
+		Count:       s.count,
+		Total:       s.total,
+		Slowest:     s.slowest,
+		SlowestName: s.slowestName,
+	}
+}
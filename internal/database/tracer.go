@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "employees-api/internal/database"
+
+// QueryObserver получает имя и длительность каждого запроса, например для метрик.
+type QueryObserver func(name string, duration time.Duration)
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name    string
+	start   time.Time
+	queries int
+}
+
+// QueryTracer замеряет каждый SQL запрос и пакет запросов: создает дочерний спан,
+// добавляет время в QueryStats из контекста и передает его наблюдателю.
+// Имя запроса берется из комментария "-- name: ..." в начале SQL.
+type QueryTracer struct {
+	observer QueryObserver
+}
+
+func NewQueryTracer(observer QueryObserver) *QueryTracer {
+	return &QueryTracer{observer: observer}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := StatementName(data.SQL)
+	ctx, _ = otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBStatement(data.SQL),
+			semconv.DBOperation(name),
+		),
+	)
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: name, start: time.Now(), queries: 1})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx, data.Err)
+}
+
+func (t *QueryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	name := "batch"
+	queries := 0
+	if data.Batch != nil {
+		queries = len(data.Batch.QueuedQueries)
+		if queries > 0 {
+			name = StatementName(data.Batch.QueuedQueries[0].SQL)
+		}
+	}
+
+	ctx, _ = otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(name)),
+	)
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: name, start: time.Now(), queries: queries})
+}
+
+func (t *QueryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	if data.Err != nil {
+		trace.SpanFromContext(ctx).RecordError(data.Err)
+	}
+}
+
+func (t *QueryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	t.end(ctx, data.Err)
+}
+
+func (t *QueryTracer) end(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	qs, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	duration := time.Since(qs.start)
+
+	if stats := queryStatsFromContext(ctx); stats != nil {
+		stats.record(qs.name, duration, qs.queries)
+	}
+	if t.observer != nil {
+		t.observer(qs.name, duration)
+	}
+}
+
+// StatementName возвращает имя запроса из комментария "-- name: employees.create"
+// в первой строке, а без него - первое ключевое слово SQL.
+func StatementName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, "-- name:"); ok {
+		name, _, _ := strings.Cut(rest, "\n")
+		return strings.TrimSpace(name)
+	}
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return "query"
+}
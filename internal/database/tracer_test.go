@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementName(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "многострочный запрос с именем",
+			sql:  "\n\t\t-- name: employees.create\n\t\tINSERT INTO employees (full_name) VALUES ($1)",
+			want: "employees.create",
+		},
+		{
+			name: "однострочный запрос с именем",
+			sql:  "-- name: employees.purge\nDELETE FROM employees WHERE id = $1",
+			want: "employees.purge",
+		},
+		{
+			name: "без имени",
+			sql:  "  select 1",
+			want: "SELECT",
+		},
+		{
+			name: "пустой запрос",
+			sql:  "",
+			want: "query",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StatementName(tt.sql))
+		})
+	}
+}
+
+func TestQueryTracer_AccumulatesStats(t *testing.T) {
+	var observed []string
+	tracer := NewQueryTracer(func(name string, _ time.Duration) {
+		observed = append(observed, name)
+	})
+
+	ctx, stats := WithQueryStats(context.Background())
+
+	for _, sql := range []string{
+		"-- name: employees.get_by_id\nSELECT 1",
+		"-- name: employees.update\nUPDATE employees SET city = $1",
+	} {
+		queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: sql})
+		time.Sleep(time.Millisecond)
+		tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{})
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue("-- name: employees.create_batch\nINSERT INTO employees DEFAULT VALUES")
+	batch.Queue("-- name: employees.create_batch\nINSERT INTO employees DEFAULT VALUES")
+	batchCtx := tracer.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{Batch: batch})
+	tracer.TraceBatchEnd(batchCtx, nil, pgx.TraceBatchEndData{})
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, 4, snapshot.Count)
+	assert.GreaterOrEqual(t, snapshot.Total, 2*time.Millisecond)
+	assert.GreaterOrEqual(t, snapshot.Slowest, time.Millisecond)
+	assert.Contains(t, []string{"employees.get_by_id", "employees.update"}, snapshot.SlowestName)
+	assert.Equal(t, []string{"employees.get_by_id", "employees.update", "employees.create_batch"}, observed)
+}
+
+func TestQueryTracer_WithoutStats(t *testing.T) {
+	tracer := NewQueryTracer(nil)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	assert.NotPanics(t, func() {
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	})
+}
@@ -1,8 +1,11 @@
 package domain
 
 import (
+	"log/slog"
 	"time"
 
+	"employees-api/internal/redact"
+
 	"github.com/google/uuid"
 )
 
@@ -13,6 +16,18 @@ type Employee struct {
 	City      string    `json:"city"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	Version   int64     `json:"-"`
+}
+
+// LogValue скрывает ФИО и телефон, если сотрудник попал в лог целиком,
+// например как значение паники.
+func (e Employee) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", e.ID.String()),
+		slog.String("fullName", redact.FullName(e.FullName)),
+		slog.String("phone", redact.Phone(e.Phone)),
+		slog.String("city", e.City),
+	)
 }
 
 type CreateEmployeeRequest struct {
@@ -20,3 +35,94 @@ type CreateEmployeeRequest struct {
 	Phone    string `json:"phone"`
 	City     string `json:"city"`
 }
+
+type UpdateEmployeeRequest struct {
+	FullName string `json:"fullName"`
+	Phone    string `json:"phone"`
+	City     string `json:"city"`
+}
+
+type EmployeePatch struct {
+	FullName *string
+	Phone    *string
+	City     *string
+}
+
+func (p EmployeePatch) IsEmpty() bool {
+	return p.FullName == nil && p.Phone == nil && p.City == nil
+}
+
+type EmployeeSort string
+
+const (
+	SortCreatedAtAsc  EmployeeSort = "createdAt"
+	SortCreatedAtDesc EmployeeSort = "-createdAt"
+)
+
+type ListEmployeesRequest struct {
+	City        string
+	PhonePrefix string
+	Sort        string
+	Cursor      string
+	Limit       int
+}
+
+type ExportEmployeesRequest struct {
+	City        string
+	PhonePrefix string
+	Sort        string
+}
+
+type EmployeeFilter struct {
+	City        string
+	PhonePrefix string
+}
+
+type EmployeeCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type ListEmployeesQuery struct {
+	Filter EmployeeFilter
+	Sort   EmployeeSort
+	After  *EmployeeCursor
+	Limit  int
+}
+
+type EmployeeList struct {
+	Items      []Employee `json:"items"`
+	NextCursor *string    `json:"nextCursor"`
+}
+
+type SearchEmployeesRequest struct {
+	Query     string
+	Threshold *float64
+	Limit     int
+}
+
+type EmployeeSearchHit struct {
+	Employee
+	Score float64 `json:"score"`
+}
+
+type EmployeeSearchResult struct {
+	Items []EmployeeSearchHit `json:"items"`
+}
+
+type BatchMode string
+
+const (
+	BatchModeAtomic     BatchMode = "atomic"
+	BatchModeBestEffort BatchMode = "bestEffort"
+)
+
+type BatchCreateEmployeesRequest struct {
+	Mode  BatchMode               `json:"mode"`
+	Items []CreateEmployeeRequest `json:"items"`
+}
+
+type BatchCreateResult struct {
+	Employee *Employee
+	Err      error
+}
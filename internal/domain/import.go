@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportStatus string
+
+const (
+	ImportStatusPending   ImportStatus = "pending"
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatXLSX ImportFormat = "xlsx"
+)
+
+// ImportColumnMapping задает, из какого столбца файла (по заголовку) брать поле сотрудника.
+type ImportColumnMapping struct {
+	FullName string `json:"fullName"`
+	Phone    string `json:"phone"`
+	City     string `json:"city"`
+}
+
+type ImportRequest struct {
+	Format  ImportFormat
+	DryRun  bool
+	Mapping ImportColumnMapping
+	Data    []byte
+}
+
+// ImportRowError хранит код правила валидации, текст по нему собирается на
+// языке запроса отчета. Message заполнен для ошибок без кода и для отчетов,
+// сохраненных до появления кодов.
+type ImportRowError struct {
+	Row     int                    `json:"row"`
+	Field   string                 `json:"field"`
+	Code    string                 `json:"code,omitempty"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// ImportJob хранит причину ошибки задачи как ErrorCode и ErrorParams, текст
+// Error собирается по ним на языке запроса. Error без кода остается у задач,
+// сохраненных до появления кодов.
+type ImportJob struct {
+	ID          uuid.UUID              `json:"id"`
+	Status      ImportStatus           `json:"status"`
+	Format      ImportFormat           `json:"format"`
+	DryRun      bool                   `json:"dryRun"`
+	Mapping     ImportColumnMapping    `json:"mapping"`
+	TotalRows   int                    `json:"totalRows"`
+	ValidRows   int                    `json:"validRows"`
+	CreatedRows int                    `json:"createdRows"`
+	FailedRows  int                    `json:"failedRows"`
+	Error       string                 `json:"error,omitempty"`
+	ErrorCode   string                 `json:"errorCode,omitempty"`
+	ErrorParams map[string]interface{} `json:"errorParams,omitempty"`
+	CreatedAt   time.Time              `json:"createdAt"`
+	StartedAt   *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time             `json:"finishedAt,omitempty"`
+	RowErrors   []ImportRowError       `json:"-"`
+}
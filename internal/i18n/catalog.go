@@ -0,0 +1,255 @@
+package i18n
+
+// Сообщения ответов об ошибках. ID не меняются при правке текста: клиенты
+// могут на них опираться.
+const (
+	MsgInvalidJSON              MessageID = "invalid_json"
+	MsgContentTypeJSON          MessageID = "content_type_json"
+	MsgContentTypeMergePatch    MessageID = "content_type_merge_patch"
+	MsgInvalidID                MessageID = "invalid_id"
+	MsgMethodNotAllowed         MessageID = "method_not_allowed"
+	MsgDuplicatePhone           MessageID = "duplicate_phone"
+	MsgForbidden                MessageID = "forbidden"
+	MsgEmployeeNotFound         MessageID = "employee_not_found"
+	MsgAPIKeyNotFound           MessageID = "api_key_not_found"
+	MsgImportJobNotFound        MessageID = "import_job_not_found"
+	MsgImportInProgress         MessageID = "import_in_progress"
+	MsgImportInvalidFile        MessageID = "import_invalid_file"  // format
+	MsgImportTooManyRows        MessageID = "import_too_many_rows" // max
+	MsgImportNoHeader           MessageID = "import_no_header"
+	MsgImportMissingColumn      MessageID = "import_missing_column" // column
+	MsgImportTimeout            MessageID = "import_timeout"
+	MsgImportInterrupted        MessageID = "import_interrupted"
+	MsgImportFailed             MessageID = "import_failed"
+	MsgInvalidMultipart         MessageID = "invalid_multipart"
+	MsgFileTooLarge             MessageID = "file_too_large"
+	MsgInternalError            MessageID = "internal_error"
+	MsgValidationError          MessageID = "validation_error"
+	MsgUnhealthy                MessageID = "unhealthy"
+	MsgBatchAborted             MessageID = "batch_aborted"
+	MsgIdempotencyKeyInProgress MessageID = "idempotency_key_in_progress"
+	MsgIdempotencyKeyReused     MessageID = "idempotency_key_reused"
+	MsgInvalidIdempotencyKey    MessageID = "invalid_idempotency_key"
+	MsgPreconditionFailed       MessageID = "precondition_failed"
+	MsgPreconditionRequired     MessageID = "precondition_required"
+	MsgVersionRequired          MessageID = "version_required"
+	MsgRateLimited              MessageID = "rate_limited"
+	MsgAuthenticationRequired   MessageID = "authentication_required"
+	MsgInvalidToken             MessageID = "invalid_token"
+	MsgInvalidAPIKey            MessageID = "invalid_api_key"
+	MsgGraphQLQueryRequired     MessageID = "graphql_query_required"
+	MsgQueryTooComplex          MessageID = "query_too_complex" // max
+)
+
+// Правила валидации. ID одновременно код правила в ответе API, параметры
+// правила перечислены в комментарии.
+const (
+	Invalid           MessageID = "invalid"
+	Required          MessageID = "required"
+	MinLength         MessageID = "min_length"   // min
+	MaxLength         MessageID = "max_length"   // max
+	LengthRange       MessageID = "length_range" // min, max
+	NameCharacters    MessageID = "name_characters"
+	PhoneFormat       MessageID = "phone_format"
+	PhonePrefixFormat MessageID = "phone_prefix_format"
+	Range             MessageID = "range"      // min, max
+	ItemCount         MessageID = "item_count" // min, max
+	MinItems          MessageID = "min_items"  // min
+	MaxItems          MessageID = "max_items"  // max
+	OneOf             MessageID = "one_of"     // values
+	Integer           MessageID = "integer"
+	Number            MessageID = "number"
+	Boolean           MessageID = "boolean"
+	InvalidCursor     MessageID = "invalid_cursor"
+	EmptyFile         MessageID = "empty_file"
+	ImportMapping     MessageID = "import_mapping"
+	DuplicateInFile   MessageID = "duplicate_in_file" // row
+	FutureTime        MessageID = "future_time"
+	APIKeyScope       MessageID = "api_key_scope" // value
+)
+
+var catalog = map[Language]map[MessageID]string{
+	RU: {
+		MsgInvalidJSON:              "Невалидный JSON",
+		MsgContentTypeJSON:          "Content-Type должен быть application/json",
+		MsgContentTypeMergePatch:    "Content-Type должен быть application/merge-patch+json",
+		MsgInvalidID:                "Невалидный ID",
+		MsgMethodNotAllowed:         "Метод не поддерживается",
+		MsgDuplicatePhone:           "Телефон уже существует",
+		MsgForbidden:                "Недостаточно прав",
+		MsgEmployeeNotFound:         "Сотрудник не найден",
+		MsgAPIKeyNotFound:           "API ключ не найден или отозван",
+		MsgImportJobNotFound:        "Задача импорта не найдена",
+		MsgImportInProgress:         "Импорт еще выполняется",
+		MsgImportInvalidFile:        "Не удалось прочитать файл {format}",
+		MsgImportTooManyRows:        "Максимум {max} {max:строка|строки|строк}",
+		MsgImportNoHeader:           "В файле нет заголовка",
+		MsgImportMissingColumn:      "В заголовке нет столбца «{column}»",
+		MsgImportTimeout:            "Импорт не уложился в отведенное время",
+		MsgImportInterrupted:        "Импорт прерван остановкой сервера",
+		MsgImportFailed:             "Внутренняя ошибка импорта",
+		MsgInvalidMultipart:         "Ожидается multipart/form-data с полем file",
+		MsgFileTooLarge:             "Файл превышает допустимый размер",
+		MsgInternalError:            "Внутренняя ошибка сервера",
+		MsgValidationError:          "Ошибка валидации",
+		MsgUnhealthy:                "Сервис недоступен",
+		MsgBatchAborted:             "Сотрудник не создан из-за ошибки в другом элементе",
+		MsgIdempotencyKeyInProgress: "Запрос с этим Idempotency-Key еще обрабатывается",
+		MsgIdempotencyKeyReused:     "Idempotency-Key уже использован с другим запросом",
+		MsgInvalidIdempotencyKey:    "Idempotency-Key не длиннее 255 символов",
+		MsgPreconditionFailed:       "Сотрудник был изменен, получите актуальную версию",
+		MsgPreconditionRequired:     "Требуется заголовок If-Match",
+		MsgVersionRequired:          "Требуется ожидаемая версия сотрудника",
+		MsgRateLimited:              "Слишком много запросов, повторите позже",
+		MsgAuthenticationRequired:   "Требуется аутентификация",
+		MsgInvalidToken:             "Невалидный или просроченный токен",
+		MsgInvalidAPIKey:            "Невалидный, отозванный или просроченный API ключ",
+		MsgGraphQLQueryRequired:     "Нужен текст запроса в поле query",
+		MsgQueryTooComplex:          "Стоимость запроса превышает предел {max}",
+
+		Invalid:           "невалидное значение",
+		Required:          "поле обязательно",
+		MinLength:         "минимум {min} {min:символ|символа|символов}",
+		MaxLength:         "максимум {max} {max:символ|символа|символов}",
+		LengthRange:       "от {min} до {max} символов",
+		NameCharacters:    "только буквы, пробелы и дефисы",
+		PhoneFormat:       "формат E.164 (+[1-15 цифр])",
+		PhonePrefixFormat: "префикс в формате E.164 (+[1-15 цифр])",
+		Range:             "от {min} до {max}",
+		ItemCount:         "от {min} до {max} элементов",
+		MinItems:          "минимум {min} {min:элемент|элемента|элементов}",
+		MaxItems:          "максимум {max} {max:элемент|элемента|элементов}",
+		OneOf:             "допустимые значения: {values}",
+		Integer:           "должно быть целым числом",
+		Number:            "должно быть числом",
+		Boolean:           "должно быть true или false",
+		InvalidCursor:     "невалидный курсор",
+		EmptyFile:         "файл пустой",
+		ImportMapping:     `JSON вида {"fullName": "ФИО", "phone": "Телефон", "city": "Город"}`,
+		DuplicateInFile:   "телефон повторяет строку {row}",
+		FutureTime:        "должно быть в будущем",
+		APIKeyScope:       "недопустимое действие {value}, ожидается employees.<действие> или imports.<действие>",
+	},
+	KK: {
+		MsgInvalidJSON:              "JSON жарамсыз",
+		MsgContentTypeJSON:          "Content-Type application/json болуы керек",
+		MsgContentTypeMergePatch:    "Content-Type application/merge-patch+json болуы керек",
+		MsgInvalidID:                "ID жарамсыз",
+		MsgMethodNotAllowed:         "Әдіске қолдау көрсетілмейді",
+		MsgDuplicatePhone:           "Бұл телефон бұрыннан бар",
+		MsgForbidden:                "Құқық жеткіліксіз",
+		MsgEmployeeNotFound:         "Қызметкер табылмады",
+		MsgAPIKeyNotFound:           "API кілті табылмады немесе кері қайтарылды",
+		MsgImportJobNotFound:        "Импорт тапсырмасы табылмады",
+		MsgImportInProgress:         "Импорт әлі орындалуда",
+		MsgImportInvalidFile:        "{format} файлын оқу мүмкін болмады",
+		MsgImportTooManyRows:        "Ең көбі {max} жол",
+		MsgImportNoHeader:           "Файлда тақырып жолы жоқ",
+		MsgImportMissingColumn:      "Тақырыпта «{column}» бағаны жоқ",
+		MsgImportTimeout:            "Импорт берілген уақытта аяқталмады",
+		MsgImportInterrupted:        "Импорт сервердің тоқтауына байланысты үзілді",
+		MsgImportFailed:             "Импорттың ішкі қатесі",
+		MsgInvalidMultipart:         "file өрісі бар multipart/form-data күтіледі",
+		MsgFileTooLarge:             "Файл рұқсат етілген өлшемнен асады",
+		MsgInternalError:            "Сервердің ішкі қатесі",
+		MsgValidationError:          "Валидация қатесі",
+		MsgUnhealthy:                "Қызмет қолжетімсіз",
+		MsgBatchAborted:             "Басқа элементтегі қатеге байланысты қызметкер құрылмады",
+		MsgIdempotencyKeyInProgress: "Осы Idempotency-Key бар сұрау әлі өңделуде",
+		MsgIdempotencyKeyReused:     "Idempotency-Key басқа сұрауда қолданылған",
+		MsgInvalidIdempotencyKey:    "Idempotency-Key 255 таңбадан аспауы керек",
+		MsgPreconditionFailed:       "Қызметкер өзгертілді, өзекті нұсқасын алыңыз",
+		MsgPreconditionRequired:     "If-Match тақырыбы қажет",
+		MsgVersionRequired:          "Қызметкердің күтілетін нұсқасы қажет",
+		MsgRateLimited:              "Сұраулар тым көп, кейінірек қайталаңыз",
+		MsgAuthenticationRequired:   "Аутентификация қажет",
+		MsgInvalidToken:             "Токен жарамсыз немесе мерзімі өткен",
+		MsgInvalidAPIKey:            "API кілті жарамсыз, кері қайтарылған немесе мерзімі өткен",
+		MsgGraphQLQueryRequired:     "query өрісінде сұрау мәтіні қажет",
+		MsgQueryTooComplex:          "Сұрау құны шектен ({max}) асады",
+
+		Invalid:           "мән жарамсыз",
+		Required:          "міндетті өріс",
+		MinLength:         "кемінде {min} таңба",
+		MaxLength:         "{max} таңбадан аспауы керек",
+		LengthRange:       "{min}-{max} таңба",
+		NameCharacters:    "тек әріптер, бос орындар және дефистер",
+		PhoneFormat:       "E.164 форматы (+[1-15 цифр])",
+		PhonePrefixFormat: "E.164 форматындағы префикс (+[1-15 цифр])",
+		Range:             "{min} мен {max} аралығында",
+		ItemCount:         "{min}-{max} элемент",
+		MinItems:          "кемінде {min} элемент",
+		MaxItems:          "{max} элементтен аспауы керек",
+		OneOf:             "рұқсат етілген мәндер: {values}",
+		Integer:           "бүтін сан болуы керек",
+		Number:            "сан болуы керек",
+		Boolean:           "true немесе false болуы керек",
+		InvalidCursor:     "курсор жарамсыз",
+		EmptyFile:         "файл бос",
+		ImportMapping:     `{"fullName": "ТАӘ", "phone": "Телефон", "city": "Қала"} түріндегі JSON`,
+		DuplicateInFile:   "телефон {row}-жолды қайталайды",
+		FutureTime:        "болашақтағы уақыт болуы керек",
+		APIKeyScope:       "{value} әрекетіне рұқсат жоқ, employees.<әрекет> немесе imports.<әрекет> күтіледі",
+	},
+	EN: {
+		MsgInvalidJSON:              "Invalid JSON",
+		MsgContentTypeJSON:          "Content-Type must be application/json",
+		MsgContentTypeMergePatch:    "Content-Type must be application/merge-patch+json",
+		MsgInvalidID:                "Invalid ID",
+		MsgMethodNotAllowed:         "Method not allowed",
+		MsgDuplicatePhone:           "Phone already exists",
+		MsgForbidden:                "Insufficient permissions",
+		MsgEmployeeNotFound:         "Employee not found",
+		MsgAPIKeyNotFound:           "API key not found or revoked",
+		MsgImportJobNotFound:        "Import job not found",
+		MsgImportInProgress:         "Import is still running",
+		MsgImportInvalidFile:        "Could not read the {format} file",
+		MsgImportTooManyRows:        "At most {max} {max:row|rows}",
+		MsgImportNoHeader:           "The file has no header row",
+		MsgImportMissingColumn:      "The header has no column \"{column}\"",
+		MsgImportTimeout:            "The import did not finish in time",
+		MsgImportInterrupted:        "The import was interrupted by a server shutdown",
+		MsgImportFailed:             "Internal import error",
+		MsgInvalidMultipart:         "Expected multipart/form-data with a file field",
+		MsgFileTooLarge:             "File exceeds the allowed size",
+		MsgInternalError:            "Internal server error",
+		MsgValidationError:          "Validation failed",
+		MsgUnhealthy:                "Service unavailable",
+		MsgBatchAborted:             "Employee not created because another item failed",
+		MsgIdempotencyKeyInProgress: "A request with this Idempotency-Key is still being processed",
+		MsgIdempotencyKeyReused:     "Idempotency-Key was already used with a different request",
+		MsgInvalidIdempotencyKey:    "Idempotency-Key must be at most 255 characters",
+		MsgPreconditionFailed:       "Employee was modified, fetch the current version",
+		MsgPreconditionRequired:     "If-Match header is required",
+		MsgVersionRequired:          "Expected employee version is required",
+		MsgRateLimited:              "Too many requests, try again later",
+		MsgAuthenticationRequired:   "Authentication required",
+		MsgInvalidToken:             "Invalid or expired token",
+		MsgInvalidAPIKey:            "Invalid, revoked or expired API key",
+		MsgGraphQLQueryRequired:     "The query field is required",
+		MsgQueryTooComplex:          "Query cost exceeds the limit of {max}",
+
+		Invalid:           "invalid value",
+		Required:          "field is required",
+		MinLength:         "at least {min} {min:character|characters}",
+		MaxLength:         "at most {max} {max:character|characters}",
+		LengthRange:       "from {min} to {max} characters",
+		NameCharacters:    "only letters, spaces and hyphens",
+		PhoneFormat:       "E.164 format (+[1-15 digits])",
+		PhonePrefixFormat: "prefix in E.164 format (+[1-15 digits])",
+		Range:             "from {min} to {max}",
+		ItemCount:         "from {min} to {max} items",
+		MinItems:          "at least {min} {min:item|items}",
+		MaxItems:          "at most {max} {max:item|items}",
+		OneOf:             "allowed values: {values}",
+		Integer:           "must be an integer",
+		Number:            "must be a number",
+		Boolean:           "must be true or false",
+		InvalidCursor:     "invalid cursor",
+		EmptyFile:         "file is empty",
+		ImportMapping:     `JSON like {"fullName": "Full name", "phone": "Phone", "city": "City"}`,
+		DuplicateInFile:   "phone duplicates row {row}",
+		FutureTime:        "must be in the future",
+		APIKeyScope:       "invalid action {value}, expected employees.<action> or imports.<action>",
+	},
+}
@@ -0,0 +1,203 @@
+// Package i18n переводит сообщения об ошибках API на русский, казахский и
+// английский. Сообщение определяется стабильным MessageID, текст подставляет
+// параметры из Params.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Language string
+
+const (
+	RU Language = "ru"
+	KK Language = "kk"
+	EN Language = "en"
+)
+
+// Default — язык ответа, если клиент не прислал Accept-Language или не
+// принимает ни один из поддерживаемых языков.
+const Default = RU
+
+var Supported = []Language{RU, KK, EN}
+
+type MessageID string
+
+// Params — параметры сообщения, например {"min": 2}.
+type Params = map[string]interface{}
+
+type languageKey struct{}
+
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// FromContext возвращает язык запроса или Default.
+func FromContext(ctx context.Context) Language {
+	if lang, ok := ctx.Value(languageKey{}).(Language); ok {
+		return lang
+	}
+	return Default
+}
+
+// T переводит сообщение на язык из контекста.
+func T(ctx context.Context, id MessageID, params Params) string {
+	return Translate(FromContext(ctx), id, params)
+}
+
+// Translate возвращает текст сообщения id на языке lang. Если перевода нет,
+// используется Default, если нет и его — сам id.
+func Translate(lang Language, id MessageID, params Params) string {
+	template, ok := catalog[lang][id]
+	if !ok {
+		if template, ok = catalog[Default][id]; !ok {
+			return string(id)
+		}
+	}
+	return render(template, params)
+}
+
+// Negotiate выбирает язык по заголовку Accept-Language (RFC 9110): язык с
+// наибольшим q, при равенстве — указанный раньше. Регион не учитывается:
+// "kk-KZ" дает kk.
+func Negotiate(header string) Language {
+	type candidate struct {
+		lang Language
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, paramsStr, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		for _, param := range strings.Split(paramsStr, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				parsed, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		lang, ok := lookupLanguage(primary)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+func lookupLanguage(primary string) (Language, bool) {
+	switch primary {
+	case "*":
+		return Default, true
+	case "kz":
+		// Код страны вместо кода языка встречается в клиентах, которые
+		// собирают заголовок вручную.
+		return KK, true
+	}
+	for _, lang := range Supported {
+		if string(lang) == primary {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// render подставляет параметры в шаблон. "{min}" заменяется значением,
+// "{min:символ|символа|символов}" — формой слова для числа min. Скобки с
+// неизвестными именами остаются как есть, поэтому в тексте можно писать JSON.
+func render(template string, params Params) string {
+	if len(params) == 0 {
+		return template
+	}
+
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(template[open:], '}')
+		if end < 0 {
+			break
+		}
+		end += open
+
+		name, forms, hasForms := strings.Cut(template[open+1:end], ":")
+		value, ok := params[name]
+		if !ok {
+			b.WriteString(template[:end+1])
+			template = template[end+1:]
+			continue
+		}
+
+		b.WriteString(template[:open])
+		if hasForms {
+			b.WriteString(pluralForm(value, strings.Split(forms, "|")))
+		} else {
+			b.WriteString(fmt.Sprint(value))
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// pluralForm выбирает форму по числу: три формы — правила русского языка
+// (1 символ, 2 символа, 5 символов), две — английского (1 character,
+// 2 characters), одна — без согласования, как в казахском.
+func pluralForm(value interface{}, forms []string) string {
+	var n int
+	switch v := value.(type) {
+	case int:
+		n = v
+	case float64:
+		// Параметры, прочитанные из JSON, приходят как float64.
+		if v != float64(int(v)) {
+			return forms[len(forms)-1]
+		}
+		n = int(v)
+	default:
+		return forms[len(forms)-1]
+	}
+	if n < 0 {
+		n = -n
+	}
+
+	switch len(forms) {
+	case 3:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return forms[0]
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return forms[1]
+		default:
+			return forms[2]
+		}
+	case 2:
+		if n == 1 {
+			return forms[0]
+		}
+		return forms[1]
+	default:
+		return forms[0]
+	}
+}
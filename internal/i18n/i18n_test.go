@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Language
+	}{
+		{"", RU},
+		{"kk", KK},
+		{"en-US,en;q=0.9", EN},
+		{"kk-KZ, ru;q=0.8", KK},
+		{"ru;q=0.5, en;q=0.9", EN},
+		{"de, en;q=0.5", EN},
+		{"de, fr", RU},
+		{"en;q=0, kk", KK},
+		{"*", RU},
+		{"kz", KK},
+		{"EN", EN},
+		{"en;q=abc, kk;q=0.1", KK},
+		{"en, kk", EN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.header))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   Language
+		id     MessageID
+		params Params
+		want   string
+	}{
+		{"русский, 2", RU, MinLength, Params{"min": 2}, "минимум 2 символа"},
+		{"русский, 1", RU, MinLength, Params{"min": 1}, "минимум 1 символ"},
+		{"русский, 11", RU, MaxLength, Params{"max": 11}, "максимум 11 символов"},
+		{"русский, 200", RU, MaxLength, Params{"max": 200}, "максимум 200 символов"},
+		{"казахский", KK, MinLength, Params{"min": 2}, "кемінде 2 таңба"},
+		{"английский, 1", EN, MinLength, Params{"min": 1}, "at least 1 character"},
+		{"английский, 2", EN, MinLength, Params{"min": 2}, "at least 2 characters"},
+		{"параметр из JSON", RU, MinLength, Params{"min": float64(2)}, "минимум 2 символа"},
+		{"несколько параметров", EN, Range, Params{"min": 1, "max": 100}, "from 1 to 100"},
+		{"JSON в тексте", RU, ImportMapping, nil, `JSON вида {"fullName": "ФИО", "phone": "Телефон", "city": "Город"}`},
+		{"неизвестный язык", Language("de"), MsgInvalidJSON, nil, "Невалидный JSON"},
+		{"неизвестное сообщение", EN, MessageID("no_such"), nil, "no_such"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Translate(tt.lang, tt.id, tt.params))
+		})
+	}
+}
+
+func TestT_LanguageFromContext(t *testing.T) {
+	assert.Equal(t, "Невалидный ID", T(context.Background(), MsgInvalidID, nil))
+	assert.Equal(t, "Invalid ID", T(WithLanguage(context.Background(), EN), MsgInvalidID, nil))
+}
+
+// Каждое сообщение переведено на все языки и использует те же параметры,
+// что и русский текст.
+func TestCatalog_Complete(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{(\w+)(?::[^}]*)?\}`)
+	names := func(template string) []string {
+		var result []string
+		for _, m := range placeholder.FindAllStringSubmatch(template, -1) {
+			result = append(result, m[1])
+		}
+		sort.Strings(result)
+		return result
+	}
+	jsonKeys := map[string]bool{"fullName": true, "phone": true, "city": true}
+	params := func(template string) []string {
+		var result []string
+		for _, name := range names(template) {
+			if !jsonKeys[name] && (len(result) == 0 || result[len(result)-1] != name) {
+				result = append(result, name)
+			}
+		}
+		return result
+	}
+
+	for _, lang := range Supported {
+		assert.Len(t, catalog[lang], len(catalog[Default]), "язык %s", lang)
+	}
+	for id, template := range catalog[Default] {
+		for _, lang := range Supported {
+			translated, ok := catalog[lang][id]
+			if assert.True(t, ok, "нет перевода %s на %s", id, lang) {
+				assert.Equal(t, params(template), params(translated), "параметры %s на %s", id, lang)
+			}
+		}
+	}
+}
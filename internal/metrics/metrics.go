@@ -0,0 +1,147 @@
+// Package metrics собирает метрики сервиса в формате Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "employees_api"
+
+// Metrics хранит собственный реестр, чтобы тесты и несколько экземпляров
+// в одном процессе не конфликтовали в глобальном prometheus.DefaultRegisterer.
+// Методы безопасно вызывать у nil: метрики просто не собираются.
+type Metrics struct {
+	registry           *prometheus.Registry
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	grpcRequests       *prometheus.CounterVec
+	grpcDuration       *prometheus.HistogramVec
+	dbQueryDuration    *prometheus.HistogramVec
+	validationFailures *prometheus.CounterVec
+	rateLimited        *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Количество HTTP запросов по шаблону маршрута, методу и статусу.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Длительность обработки HTTP запросов.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Количество unary вызовов gRPC по полному имени метода и коду статуса.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Длительность обработки unary вызовов gRPC.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Длительность операций репозиториев с БД.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		validationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "validation_failures_total",
+			Help:      "Количество ошибок валидации по полям.",
+		}, []string{"field"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Количество запросов, отклоненных ограничением частоты, по правилу.",
+		}, []string{"rule"}),
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcRequests,
+		m.grpcDuration,
+		m.dbQueryDuration,
+		m.validationFailures,
+		m.rateLimited,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// RegisterPool добавляет метрики пула соединений, которые снимаются с pool.Stat() при каждом сборе.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	if m == nil {
+		return
+	}
+	m.registry.MustRegister(newPoolCollector(pool))
+}
+
+// Handler отдает метрики в текстовом формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		method = "OTHER"
+	}
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(route, method, code).Inc()
+	m.httpDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+// ObserveGRPCRequest учитывает unary вызов gRPC. method - полное имя вида
+// /employees.v1.EmployeesService/GetEmployee: неизвестные методы сервер
+// отклоняет до перехватчиков, поэтому число меток ограничено.
+func (m *Metrics) ObserveGRPCRequest(method, code string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveQuery(operation string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.dbQueryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveValidationFailure(field string) {
+	if m == nil {
+		return
+	}
+	m.validationFailures.WithLabelValues(field).Inc()
+}
+
+func (m *Metrics) ObserveRateLimited(rule string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(rule).Inc()
+}
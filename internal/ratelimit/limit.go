@@ -0,0 +1,190 @@
+// Package ratelimit ограничивает частоту запросов клиентов алгоритмом token
+// bucket. Состояние корзин хранится в Store: в памяти процесса или, позже,
+// в общем хранилище для нескольких экземпляров сервиса.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit — корзина на Burst запросов, которая пополняется на Requests запросов
+// за Period. Нулевой Limit означает отсутствие ограничения.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+// interval — время пополнения корзины на один запрос.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s:%d", l.Requests, l.Period, l.Burst)
+}
+
+var periodUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// ParseLimit разбирает лимит вида "10/s", "600/m:50" (50 — размер корзины,
+// по умолчанию равен числу запросов) или "off".
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" {
+		return Limit{}, nil
+	}
+
+	rate, burstStr, hasBurst := strings.Cut(s, ":")
+	requestsStr, unit, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("невалидный лимит %q, ожидается <запросов>/<s|m|h>[:<корзина>]", s)
+	}
+
+	requests, err := strconv.Atoi(requestsStr)
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("невалидный лимит %q: число запросов должно быть положительным", s)
+	}
+	period, ok := periodUnits[unit]
+	if !ok {
+		return Limit{}, fmt.Errorf("невалидный лимит %q: единица периода s, m или h", s)
+	}
+
+	burst := requests
+	if hasBurst {
+		burst, err = strconv.Atoi(burstStr)
+		if err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("невалидный лимит %q: размер корзины должен быть положительным", s)
+		}
+	}
+
+	// Store делит на interval и считает емкость корзины как Burst*interval:
+	// нулевой интервал или переполнение емкости сломали бы каждый запрос.
+	limit := Limit{Requests: requests, Period: period, Burst: burst}
+	if limit.interval() <= 0 {
+		return Limit{}, fmt.Errorf("невалидный лимит %q: больше одного запроса в наносекунду", s)
+	}
+	if int64(burst) > math.MaxInt64/int64(limit.interval()) {
+		return Limit{}, fmt.Errorf("невалидный лимит %q: слишком большой размер корзины", s)
+	}
+	return limit, nil
+}
+
+// Rule задает лимит для метода и шаблона маршрута. "*" в Method или Route
+// подходит к любому значению; в Route сегмент вида "{id}" или "{id}:restore"
+// подходит к любому непустому значению с тем же окончанием.
+type Rule struct {
+	Method string
+	Route  string
+	Limit  Limit
+}
+
+// ParseRule разбирает правило вида "POST /v1/employees=10/s:20".
+func ParseRule(s string) (Rule, error) {
+	target, limitStr, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok {
+		return Rule{}, fmt.Errorf("невалидное правило %q, ожидается <метод> <маршрут>=<лимит>", s)
+	}
+	method, route, ok := strings.Cut(strings.TrimSpace(target), " ")
+	route = strings.TrimSpace(route)
+	if !ok || route == "" || (route != "*" && !strings.HasPrefix(route, "/")) {
+		return Rule{}, fmt.Errorf("невалидное правило %q, ожидается <метод> <маршрут>=<лимит>", s)
+	}
+
+	method = strings.ToUpper(method)
+	if method != "*" && !knownMethods[method] {
+		return Rule{}, fmt.Errorf("невалидное правило %q: неизвестный метод %s", s, method)
+	}
+
+	limit, err := ParseLimit(limitStr)
+	if err != nil {
+		return Rule{}, fmt.Errorf("невалидное правило %q: %w", s, err)
+	}
+	return Rule{Method: method, Route: route, Limit: limit}, nil
+}
+
+func (r Rule) String() string {
+	return r.Method + " " + r.Route
+}
+
+// Rules выбирает правило для запроса. Точное совпадение метода и маршрута
+// важнее "*", маршрут важнее метода, из равных побеждает более длинный маршрут:
+// "/v1/employees/{id}:restore" точнее "/v1/employees/{id}".
+type Rules struct {
+	rules    []Rule
+	fallback Rule
+}
+
+// NewRules создает набор правил; fallback применяется к запросам, не
+// подошедшим ни к одному правилу.
+func NewRules(fallback Limit, rules ...Rule) *Rules {
+	return &Rules{rules: rules, fallback: Rule{Method: "*", Route: "*", Limit: fallback}}
+}
+
+func (rs *Rules) Match(method, path string) Rule {
+	best, bestScore := rs.fallback, 0
+	for _, rule := range rs.rules {
+		score := 0
+		switch {
+		case rule.Method == method:
+			score++
+		case rule.Method != "*":
+			continue
+		}
+		switch {
+		case rule.Route == "*":
+		case routeMatches(rule.Route, path):
+			score += 2
+		default:
+			continue
+		}
+		if score > bestScore || (score == bestScore && score > 0 && len(rule.Route) > len(best.Route)) {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+func routeMatches(route, path string) bool {
+	routeSegments := strings.Split(route, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(routeSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range routeSegments {
+		open := strings.IndexByte(segment, '{')
+		end := strings.IndexByte(segment, '}')
+		if open < 0 || end < open {
+			if segment != pathSegments[i] {
+				return false
+			}
+			continue
+		}
+		prefix, suffix := segment[:open], segment[end+1:]
+		value := pathSegments[i]
+		if len(value) <= len(prefix)+len(suffix) || !strings.HasPrefix(value, prefix) || !strings.HasSuffix(value, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "10/s", want: Limit{Requests: 10, Period: time.Second, Burst: 10}},
+		{in: "600/m:50", want: Limit{Requests: 600, Period: time.Minute, Burst: 50}},
+		{in: "off", want: Limit{}},
+		{in: "10", wantErr: true},
+		{in: "0/s", wantErr: true},
+		{in: "10/d", wantErr: true},
+		{in: "10/s:0", wantErr: true},
+		{in: "1000000000/s", want: Limit{Requests: 1000000000, Period: time.Second, Burst: 1000000000}},
+		{in: "2000000000/s", wantErr: true},
+		{in: "1/h:9000000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRule_Errors(t *testing.T) {
+	for _, in := range []string{"POST /v1/employees", "/v1/employees=10/s", "FETCH /v1/employees=10/s", "POST v1=10/s", "POST /v1/employees=fast"} {
+		_, err := ParseRule(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestRules_Match(t *testing.T) {
+	var rules []Rule
+	for _, spec := range []string{
+		"POST /v1/employees=1/s",
+		"* /v1/employees=2/s",
+		"DELETE *=3/s",
+		"* /v1/employees/{id}=4/s",
+		"POST /v1/employees/{id}:restore=5/s",
+		"* /v1/healthz=off",
+	} {
+		rule, err := ParseRule(spec)
+		require.NoError(t, err)
+		rules = append(rules, rule)
+	}
+	fallback := Limit{Requests: 100, Period: time.Second, Burst: 100}
+	rs := NewRules(fallback, rules...)
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{"POST", "/v1/employees", 1},
+		{"GET", "/v1/employees", 2},
+		{"DELETE", "/v1/employees", 2},
+		{"DELETE", "/v1/imports/1", 3},
+		{"GET", "/v1/employees/123", 4},
+		{"POST", "/v1/employees/123:restore", 5},
+		{"GET", "/v1/employees/123:restore", 4},
+		{"GET", "/v1/employees:batch", 100},
+		{"GET", "/v1/healthz", 0},
+		{"GET", "/v1/imports", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, rs.Match(tt.method, tt.path).Limit.Requests)
+		})
+	}
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryStore(WithClock(func() time.Time { return now }))
+	limit := Limit{Requests: 2, Period: time.Second, Burst: 3}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		result, err := store.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, result.Reset)
+
+	other, err := store.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "корзины клиентов независимы")
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "за интервал пополнения появился токен")
+	assert.Equal(t, 0, result.Remaining)
+
+	now = now.Add(time.Hour)
+	result, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Remaining, "корзина не переполняется сверх Burst")
+	assert.Equal(t, 1, store.Len(), "полные корзины удаляются")
+}
+
+func TestMemoryStore_Unlimited(t *testing.T) {
+	store := NewMemoryStore()
+	for i := 0; i < 100; i++ {
+		result, err := store.Take(context.Background(), "client", Limit{})
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	assert.Equal(t, 0, store.Len())
+}
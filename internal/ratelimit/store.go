@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result — состояние корзины после попытки взять токен.
+type Result struct {
+	Allowed bool
+	// Limit — размер корзины, Remaining — токенов после запроса.
+	Limit     int
+	Remaining int
+	// RetryAfter — через сколько появится следующий токен, если запрос отклонен.
+	RetryAfter time.Duration
+	// Reset — через сколько корзина наполнится полностью.
+	Reset time.Duration
+}
+
+// Store хранит корзины клиентов. Take атомарно берет токен из корзины key с
+// параметрами limit; общие хранилища (например, Redis) реализуют тот же контракт.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	// full — момент, когда корзина станет полной. Хранить его вместо числа
+	// токенов удобнее: пополнение считается без отдельного таймера (GCRA).
+	full time.Time
+}
+
+// MemoryStore хранит корзины в памяти процесса. Лимиты действуют на каждый
+// экземпляр сервиса отдельно.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+type MemoryStoreOption func(*MemoryStore)
+
+// WithClock подменяет источник времени, например в тестах.
+func WithClock(now func() time.Time) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.now = now
+	}
+}
+
+func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
+	s := &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.lastSweep = s.now()
+	return s
+}
+
+// sweepInterval — как часто удалять полные корзины, чтобы память не росла
+// с числом разовых клиентов.
+const sweepInterval = time.Minute
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	interval := limit.interval()
+	capacity := time.Duration(limit.Burst) * interval
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{full: now}
+		s.buckets[key] = b
+	}
+	full := b.full
+	if full.Before(now) {
+		full = now
+	}
+
+	// Корзина пуста, если до полного наполнения больше capacity - interval:
+	// нового токена придется ждать.
+	next := full.Add(interval)
+	if next.Sub(now) > capacity {
+		return Result{
+			Allowed:    false,
+			Limit:      limit.Burst,
+			Remaining:  0,
+			RetryAfter: next.Sub(now) - capacity,
+			Reset:      full.Sub(now),
+		}, nil
+	}
+
+	b.full = next
+	return Result{
+		Allowed:   true,
+		Limit:     limit.Burst,
+		Remaining: int((capacity - next.Sub(now)) / interval),
+		Reset:     next.Sub(now),
+	}, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !b.full.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Len возвращает число хранимых корзин.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
@@ -0,0 +1,137 @@
+// Package redact маскирует персональные данные сотрудников (телефоны и ФИО)
+// в тексте, который уходит в логи и в сообщения об ошибках.
+package redact
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const mask = "***"
+
+var (
+	// phonePattern находит номера E.164 внутри произвольного текста.
+	phonePattern = regexp.MustCompile(`\+[1-9]\d{6,14}`)
+	// namePattern находит два и более подряд идущих слова с заглавной буквы
+	// одного алфавита: так ФИО выглядит в сообщениях pgx, значениях паник и
+	// выводе %v. Алфавит тот же, что допускает валидация ФИО.
+	namePattern = regexp.MustCompile(
+		`[А-ЯЁӘІҢҒҮҰҚӨҺ][а-яёәіңғүұқөһ]+(?:[ \t-]+[А-ЯЁӘІҢҒҮҰҚӨҺ][а-яёәіңғүұқөһ]+)+` +
+			`|[A-Z][a-z]+(?:[ \t-]+[A-Z][a-z]+)+`)
+)
+
+// Phone маскирует середину номера: +79991234567 -> +7999***4567.
+// У коротких номеров открыто меньше цифр, скрыто всегда не меньше трех.
+func Phone(phone string) string {
+	digits := strings.TrimPrefix(phone, "+")
+	tail := 4
+	if len(digits) < 10 {
+		tail = 2
+	}
+	head := min(4, len(digits)-tail-3)
+	if head < 0 {
+		head, tail = 0, max(0, len(digits)-3)
+	}
+	return phone[:len(phone)-len(digits)] + digits[:head] + mask + digits[len(digits)-tail:]
+}
+
+// FullName оставляет первую букву каждого слова: "Иван Иванов" -> "И*** И***".
+func FullName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		r, _ := utf8.DecodeRuneInString(word)
+		words[i] = string(r) + mask
+	}
+	return strings.Join(words, " ")
+}
+
+// Text маскирует телефоны и ФИО в произвольном тексте: сначала значения,
+// запомненные в ctx за время запроса, затем все, что похоже на телефон или ФИО.
+func Text(ctx context.Context, s string) string {
+	if s == "" {
+		return s
+	}
+	if k := knownFromContext(ctx); k != nil {
+		s = k.replace(s)
+	}
+	s = phonePattern.ReplaceAllStringFunc(s, Phone)
+	return namePattern.ReplaceAllStringFunc(s, FullName)
+}
+
+type knownKey struct{}
+
+// known — значения, пришедшие в запросе. Они маскируются даже там, где
+// шаблоны их не узнают, например ФИО в нижнем регистре.
+type known struct {
+	mu     sync.Mutex
+	values map[string]string
+	sorted []string
+}
+
+// NewContext добавляет в контекст пустой набор значений для RememberName и RememberPhone.
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, knownKey{}, &known{values: make(map[string]string)})
+}
+
+func knownFromContext(ctx context.Context) *known {
+	if ctx == nil {
+		return nil
+	}
+	k, _ := ctx.Value(knownKey{}).(*known)
+	return k
+}
+
+// RememberName запоминает ФИО и отдельные слова длиннее двух букв.
+// Без NewContext вызов ничего не делает.
+func RememberName(ctx context.Context, name string) {
+	k := knownFromContext(ctx)
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if words := strings.Fields(name); len(words) > 0 {
+		k.add(name, FullName(name))
+		for _, word := range words {
+			if utf8.RuneCountInString(word) > 2 {
+				k.add(word, FullName(word))
+			}
+		}
+	}
+}
+
+// RememberPhone запоминает телефон, в том числе слишком короткий для phonePattern.
+func RememberPhone(ctx context.Context, phone string) {
+	k := knownFromContext(ctx)
+	if k == nil || phone == "" {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.add(phone, Phone(phone))
+}
+
+func (k *known) add(value, masked string) {
+	if _, ok := k.values[value]; ok {
+		return
+	}
+	k.values[value] = masked
+	k.sorted = append(k.sorted, value)
+	// Длинные значения заменяются первыми, чтобы ФИО целиком не разбивалось на слова.
+	sort.Slice(k.sorted, func(i, j int) bool {
+		return len(k.sorted[i]) > len(k.sorted[j])
+	})
+}
+
+func (k *known) replace(s string) string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, value := range k.sorted {
+		s = strings.ReplaceAll(s, value, k.values[value])
+	}
+	return s
+}
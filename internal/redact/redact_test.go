@@ -0,0 +1,82 @@
+package redact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"+79991234567", "+7999***4567"},
+		{"+77011234567", "+7701***4567"},
+		{"+123456789012345", "+1234***2345"},
+		{"+1234567", "+12***67"},
+		{"+12", "+***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			assert.Equal(t, tt.want, Phone(tt.phone))
+		})
+	}
+}
+
+func TestFullName(t *testing.T) {
+	assert.Equal(t, "И*** И***", FullName("Иван Иванов"))
+	assert.Equal(t, "А***", FullName("Анна-Мария"))
+	assert.Equal(t, "J*** D***", FullName("  John   Doe "))
+	assert.Equal(t, "", FullName(""))
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "телефон в ошибке pgx",
+			in:   `ошибка создания сотрудника: failed to encode args[1]: unable to encode "+79991234567"`,
+			want: `ошибка создания сотрудника: failed to encode args[1]: unable to encode "+7999***4567"`,
+		},
+		{
+			name: "ФИО и телефон в выводе структуры",
+			in:   "{FullName:Иван Иванов Phone:+77011234567 City:Алматы}",
+			want: "{FullName:И*** И*** Phone:+7701***4567 City:Алматы}",
+		},
+		{
+			name: "текст без персональных данных",
+			in:   "ошибка получения сотрудника: context deadline exceeded",
+			want: "ошибка получения сотрудника: context deadline exceeded",
+		},
+		{
+			name: "уже замаскированный текст",
+			in:   "И*** И*** +7999***4567",
+			want: "И*** И*** +7999***4567",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Text(context.Background(), tt.in))
+		})
+	}
+}
+
+func TestText_RememberedValues(t *testing.T) {
+	ctx := NewContext(context.Background())
+	RememberName(ctx, "иван петров")
+	RememberPhone(ctx, "+123")
+
+	got := Text(ctx, "failing row contains (иван петров, +123); петров")
+	assert.Equal(t, "failing row contains (и*** п***, +***); п***", got)
+
+	// Без NewContext значения не запоминаются и не мешают друг другу.
+	RememberName(context.Background(), "иван петров")
+	assert.Equal(t, "иван петров", Text(context.Background(), "иван петров"))
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"employees-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_at, last_used_at, expires_at, rotated_at, revoked_at`
+
+func scanAPIKey(row pgx.Row) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&key.Scopes,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.RotatedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	query := `
+		-- name: api_keys.create
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, key.Name, key.Prefix, key.Hash, key.Scopes, key.ExpiresAt).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания API ключа: %w", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	query := `
+		-- name: api_keys.get_by_hash
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ошибка получения API ключа: %w", err)
+	}
+	return key, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	query := `
+		-- name: api_keys.list
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка API ключей: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения API ключа: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка получения списка API ключей: %w", err)
+	}
+	return keys, nil
+}
+
+// Rotate заменяет хеш и префикс неотозванного ключа; прежнее значение сразу
+// перестает приниматься.
+func (r *APIKeyRepository) Rotate(ctx context.Context, id uuid.UUID, hash, prefix string) (*domain.APIKey, error) {
+	query := `
+		-- name: api_keys.rotate
+		UPDATE api_keys
+		SET key_hash = $2, prefix = $3, rotated_at = now(), last_used_at = NULL
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, query, id, hash, prefix))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ошибка ротации API ключа: %w", err)
+	}
+	return key, nil
+}
+
+// Revoke отзывает ключ. Повторный отзыв не меняет revoked_at.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, "-- name: api_keys.revoke\nUPDATE api_keys SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ошибка отзыва API ключа: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, "-- name: api_keys.touch_last_used\nUPDATE api_keys SET last_used_at = now() WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления времени использования API ключа: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"employees-api/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool}
+}
+
+// Reserve занимает ключ для обработки запроса. Если ключ уже существует и не
+// истек, возвращается сохраненная запись и reserved = false. Незавершенный
+// ключ, занятый раньше lockTimeout назад (created_at - время резервирования),
+// считается брошенным упавшим запросом и занимается заново.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, fingerprint string, ttl, lockTimeout time.Duration) (*domain.IdempotencyRecord, bool, error) {
+	query := `
+		-- name: idempotency.reserve
+		INSERT INTO idempotency_keys (key, fingerprint, expires_at)
+		VALUES ($1, $2, now() + $3::interval)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			response_headers = NULL,
+			response_body = NULL,
+			created_at = now(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < now()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < now() - $4::interval)
+		RETURNING key
+	`
+
+	var reservedKey string
+	err := r.pool.QueryRow(ctx, query, key, fingerprint, ttl, lockTimeout).Scan(&reservedKey)
+	if err == nil {
+		return &domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("ошибка резервирования ключа идемпотентности: %w", err)
+	}
+
+	record, err := r.get(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+func (r *IdempotencyRepository) get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+		-- name: idempotency.get
+		SELECT key, fingerprint, COALESCE(status_code, 0), response_headers, response_body
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	var record domain.IdempotencyRecord
+	err := r.pool.QueryRow(ctx, query, key).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&record.StatusCode,
+		&record.Headers,
+		&record.Body,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ошибка получения ключа идемпотентности: %w", err)
+	}
+
+	return &record, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, headers map[string]string, body []byte) error {
+	query := `
+		-- name: idempotency.complete
+		UPDATE idempotency_keys
+		SET status_code = $2, response_headers = $3, response_body = $4
+		WHERE key = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, key, statusCode, headers, body)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения ответа идемпотентности: %w", err)
+	}
+	return nil
+}
+
+// Release освобождает незавершенный ключ, чтобы клиент мог повторить запрос.
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.pool.Exec(ctx, "-- name: idempotency.release\nDELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL", key)
+	if err != nil {
+		return fmt.Errorf("ошибка освобождения ключа идемпотентности: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, "-- name: idempotency.delete_expired\nDELETE FROM idempotency_keys WHERE expires_at < now()")
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления истекших ключей идемпотентности: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
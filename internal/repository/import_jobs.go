@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"employees-api/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImportJobRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewImportJobRepository(pool *pgxpool.Pool) *ImportJobRepository {
+	return &ImportJobRepository{pool: pool}
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context, job *domain.ImportJob) error {
+	query := `
+		-- name: import_jobs.create
+		INSERT INTO import_jobs (status, format, dry_run, mapping)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, job.Status, job.Format, job.DryRun, job.Mapping).Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания задачи импорта: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	query := `
+		-- name: import_jobs.get_by_id
+		SELECT id, status, format, dry_run, mapping, total_rows, valid_rows, created_rows, failed_rows,
+			error, error_code, error_params, created_at, started_at, finished_at
+		FROM import_jobs
+		WHERE id = $1
+	`
+
+	var job domain.ImportJob
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&job.ID,
+		&job.Status,
+		&job.Format,
+		&job.DryRun,
+		&job.Mapping,
+		&job.TotalRows,
+		&job.ValidRows,
+		&job.CreatedRows,
+		&job.FailedRows,
+		&job.Error,
+		&job.ErrorCode,
+		&job.ErrorParams,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ошибка получения задачи импорта: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (r *ImportJobRepository) GetRowErrors(ctx context.Context, id uuid.UUID) ([]domain.ImportRowError, error) {
+	var rowErrors []domain.ImportRowError
+	err := r.pool.QueryRow(ctx, "-- name: import_jobs.get_row_errors\nSELECT row_errors FROM import_jobs WHERE id = $1", id).Scan(&rowErrors)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ошибка получения отчета импорта: %w", err)
+	}
+
+	return rowErrors, nil
+}
+
+func (r *ImportJobRepository) MarkRunning(ctx context.Context, id uuid.UUID) error {
+	query := `
+		-- name: import_jobs.mark_running
+		UPDATE import_jobs
+		SET status = $2, started_at = now()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, domain.ImportStatusRunning); err != nil {
+		return fmt.Errorf("ошибка обновления задачи импорта: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) Finish(ctx context.Context, job *domain.ImportJob) error {
+	query := `
+		-- name: import_jobs.finish
+		UPDATE import_jobs
+		SET status = $2, total_rows = $3, valid_rows = $4, created_rows = $5, failed_rows = $6,
+			row_errors = $7, error = $8, error_code = $9, error_params = $10, finished_at = now()
+		WHERE id = $1
+	`
+
+	rowErrors := job.RowErrors
+	if rowErrors == nil {
+		rowErrors = []domain.ImportRowError{}
+	}
+
+	_, err := r.pool.Exec(ctx, query, job.ID, job.Status, job.TotalRows, job.ValidRows, job.CreatedRows,
+		job.FailedRows, rowErrors, job.Error, job.ErrorCode, job.ErrorParams)
+	if err != nil {
+		return fmt.Errorf("ошибка завершения задачи импорта: %w", err)
+	}
+	return nil
+}
+
+// FailStale завершает незаконченные задачи старше maxAge с кодом ошибки code.
+// Файл задачи хранится только в памяти принявшего его процесса, поэтому такие
+// задачи уже не продолжатся.
+func (r *ImportJobRepository) FailStale(ctx context.Context, maxAge time.Duration, code string) (int64, error) {
+	query := `
+		-- name: import_jobs.fail_stale
+		UPDATE import_jobs
+		SET status = $1, error_code = $2, finished_at = now()
+		WHERE status IN ($3, $4) AND created_at < now() - $5::interval
+	`
+
+	tag, err := r.pool.Exec(ctx, query, domain.ImportStatusFailed, code,
+		domain.ImportStatusPending, domain.ImportStatusRunning, maxAge)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка завершения прерванных задач импорта: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
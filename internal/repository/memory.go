@@ -0,0 +1,392 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"employees-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type memoryEmployee struct {
+	domain.Employee
+	deleted bool
+}
+
+// MemoryEmployeeStore хранит сотрудников в памяти процесса. Предназначен для тестов
+// сервисного слоя без PostgreSQL и повторяет семантику EmployeeRepository:
+// уникальность телефона среди неудаленных записей, мягкое удаление и версии.
+type MemoryEmployeeStore struct {
+	mu        sync.RWMutex
+	employees map[uuid.UUID]*memoryEmployee
+}
+
+func NewMemoryEmployeeStore() *MemoryEmployeeStore {
+	return &MemoryEmployeeStore{employees: make(map[uuid.UUID]*memoryEmployee)}
+}
+
+// now округляет время до микросекунд, как timestamptz в PostgreSQL.
+func (s *MemoryEmployeeStore) now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// phoneTaken сообщает, занят ли телефон неудаленным сотрудником, кроме except.
+// Вызывается под блокировкой.
+func (s *MemoryEmployeeStore) phoneTaken(phone string, except uuid.UUID) bool {
+	for id, emp := range s.employees {
+		if id != except && !emp.deleted && emp.Phone == phone {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryEmployeeStore) insert(req domain.CreateEmployeeRequest) *domain.Employee {
+	now := s.now()
+	emp := &memoryEmployee{Employee: domain.Employee{
+		ID:        uuid.New(),
+		FullName:  req.FullName,
+		Phone:     req.Phone,
+		City:      req.City,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}}
+	s.employees[emp.ID] = emp
+
+	created := emp.Employee
+	return &created
+}
+
+func (s *MemoryEmployeeStore) Create(ctx context.Context, req domain.CreateEmployeeRequest) (*domain.Employee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.phoneTaken(req.Phone, uuid.Nil) {
+		return nil, ErrDuplicatePhone
+	}
+	return s.insert(req), nil
+}
+
+func (s *MemoryEmployeeStore) CreateBatch(ctx context.Context, reqs []domain.CreateEmployeeRequest, atomic bool) ([]domain.BatchCreateResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]domain.BatchCreateResult, len(reqs))
+	var created []uuid.UUID
+	failed := false
+
+	for i, req := range reqs {
+		if s.phoneTaken(req.Phone, uuid.Nil) {
+			results[i].Err = ErrDuplicatePhone
+			failed = true
+			continue
+		}
+		results[i].Employee = s.insert(req)
+		created = append(created, results[i].Employee.ID)
+	}
+
+	if atomic && failed {
+		for _, id := range created {
+			delete(s.employees, id)
+		}
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = domain.BatchCreateResult{Err: ErrBatchAborted}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (s *MemoryEmployeeStore) GetByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	emp, ok := s.employees[id]
+	if !ok || emp.deleted {
+		return nil, ErrNotFound
+	}
+
+	found := emp.Employee
+	return &found, nil
+}
+
+func (s *MemoryEmployeeStore) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Employee, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found := make([]domain.Employee, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		emp, ok := s.employees[id]
+		if !ok || emp.deleted || seen[id] {
+			continue
+		}
+		seen[id] = true
+		found = append(found, emp.Employee)
+	}
+	return found, nil
+}
+
+// modify применяет fn к неудаленному сотруднику с ожидаемой версией.
+// expectedVersion = 0 означает безусловное изменение.
+func (s *MemoryEmployeeStore) modify(id uuid.UUID, expectedVersion int64, fn func(emp *memoryEmployee) error) (*domain.Employee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emp, ok := s.employees[id]
+	if !ok || emp.deleted {
+		return nil, ErrNotFound
+	}
+	if expectedVersion != 0 && emp.Version != expectedVersion {
+		return nil, ErrVersionMismatch
+	}
+
+	if err := fn(emp); err != nil {
+		return nil, err
+	}
+	emp.UpdatedAt = s.now()
+	emp.Version++
+
+	updated := emp.Employee
+	return &updated, nil
+}
+
+func (s *MemoryEmployeeStore) Update(ctx context.Context, id uuid.UUID, expectedVersion int64, req domain.UpdateEmployeeRequest) (*domain.Employee, error) {
+	return s.modify(id, expectedVersion, func(emp *memoryEmployee) error {
+		if s.phoneTaken(req.Phone, id) {
+			return ErrDuplicatePhone
+		}
+		emp.FullName = req.FullName
+		emp.Phone = req.Phone
+		emp.City = req.City
+		return nil
+	})
+}
+
+func (s *MemoryEmployeeStore) Patch(ctx context.Context, id uuid.UUID, expectedVersion int64, patch domain.EmployeePatch) (*domain.Employee, error) {
+	return s.modify(id, expectedVersion, func(emp *memoryEmployee) error {
+		if patch.Phone != nil && s.phoneTaken(*patch.Phone, id) {
+			return ErrDuplicatePhone
+		}
+		if patch.FullName != nil {
+			emp.FullName = *patch.FullName
+		}
+		if patch.Phone != nil {
+			emp.Phone = *patch.Phone
+		}
+		if patch.City != nil {
+			emp.City = *patch.City
+		}
+		return nil
+	})
+}
+
+func (s *MemoryEmployeeStore) Delete(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
+	_, err := s.modify(id, expectedVersion, func(emp *memoryEmployee) error {
+		emp.deleted = true
+		return nil
+	})
+	return err
+}
+
+func (s *MemoryEmployeeStore) Restore(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emp, ok := s.employees[id]
+	if !ok || !emp.deleted {
+		return nil, ErrNotFound
+	}
+	if s.phoneTaken(emp.Phone, id) {
+		return nil, ErrDuplicatePhone
+	}
+
+	emp.deleted = false
+	emp.UpdatedAt = s.now()
+	emp.Version++
+
+	restored := emp.Employee
+	return &restored, nil
+}
+
+func (s *MemoryEmployeeStore) Purge(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.employees[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.employees, id)
+	return nil
+}
+
+func (s *MemoryEmployeeStore) FindExistingPhones(ctx context.Context, phones []string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(phones))
+	for _, phone := range phones {
+		wanted[phone] = true
+	}
+
+	existing := []string{}
+	for _, emp := range s.employees {
+		if !emp.deleted && wanted[emp.Phone] {
+			existing = append(existing, emp.Phone)
+		}
+	}
+	return existing, nil
+}
+
+func (s *MemoryEmployeeStore) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (s *MemoryEmployeeStore) List(ctx context.Context, q domain.ListEmployeesQuery) ([]domain.Employee, error) {
+	employees := make([]domain.Employee, 0, q.Limit)
+	err := s.Stream(ctx, q, func(emp *domain.Employee) error {
+		employees = append(employees, *emp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return employees, nil
+}
+
+// Stream делает снимок подходящих записей и вызывает fn без удержания блокировки.
+func (s *MemoryEmployeeStore) Stream(ctx context.Context, q domain.ListEmployeesQuery, fn func(*domain.Employee) error) error {
+	desc := q.Sort == domain.SortCreatedAtDesc
+
+	s.mu.RLock()
+	var matched []domain.Employee
+	for _, emp := range s.employees {
+		if emp.deleted {
+			continue
+		}
+		if q.Filter.City != "" && emp.City != q.Filter.City {
+			continue
+		}
+		if q.Filter.PhonePrefix != "" && !strings.HasPrefix(emp.Phone, q.Filter.PhonePrefix) {
+			continue
+		}
+		if q.After != nil {
+			cmp := compareCreatedAtID(emp.CreatedAt, emp.ID, q.After.CreatedAt, q.After.ID)
+			if (!desc && cmp <= 0) || (desc && cmp >= 0) {
+				continue
+			}
+		}
+		matched = append(matched, emp.Employee)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		cmp := compareCreatedAtID(matched[i].CreatedAt, matched[i].ID, matched[j].CreatedAt, matched[j].ID)
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+
+	for i := range matched {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&matched[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareCreatedAtID сравнивает пары (created_at, id) так же, как PostgreSQL:
+// uuid упорядочиваются побайтово.
+func compareCreatedAtID(aTime time.Time, aID uuid.UUID, bTime time.Time, bID uuid.UUID) int {
+	switch {
+	case aTime.Before(bTime):
+		return -1
+	case aTime.After(bTime):
+		return 1
+	}
+	return bytes.Compare(aID[:], bID[:])
+}
+
+func (s *MemoryEmployeeStore) Search(ctx context.Context, query string, threshold float64, limit int) ([]domain.EmployeeSearchHit, error) {
+	queryTrigrams := trigrams(query)
+
+	s.mu.RLock()
+	var hits []domain.EmployeeSearchHit
+	for _, emp := range s.employees {
+		if emp.deleted {
+			continue
+		}
+		score := similarity(queryTrigrams, trigrams(emp.FullName))
+		if score < threshold {
+			continue
+		}
+		hits = append(hits, domain.EmployeeSearchHit{
+			Employee: emp.Employee,
+			Score:    math.Round(score*10000) / 10000,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return bytes.Compare(hits[i].ID[:], hits[j].ID[:]) < 0
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	if hits == nil {
+		hits = []domain.EmployeeSearchHit{}
+	}
+
+	return hits, nil
+}
+
+// trigrams повторяет разбиение pg_trgm: строка приводится к нижнему регистру,
+// делится на слова из букв и цифр, каждое слово дополняется двумя пробелами
+// в начале и одним в конце.
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// similarity - доля общих триграмм, как similarity() в pg_trgm.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			common++
+		}
+	}
+	return float64(float32(common) / float32(len(a)+len(b)-common))
+}
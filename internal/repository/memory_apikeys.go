@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"employees-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// MemoryAPIKeyStore хранит API ключи в памяти процесса для тестов без PostgreSQL.
+type MemoryAPIKeyStore struct {
+	mu   sync.RWMutex
+	keys map[uuid.UUID]*domain.APIKey
+}
+
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: make(map[uuid.UUID]*domain.APIKey)}
+}
+
+func (s *MemoryAPIKeyStore) now() *time.Time {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	return &now
+}
+
+// cloneAPIKey копирует ключ, чтобы вызывающий код не менял хранимое состояние.
+func cloneAPIKey(key *domain.APIKey) *domain.APIKey {
+	c := *key
+	c.Scopes = append([]string(nil), key.Scopes...)
+	return &c
+}
+
+func (s *MemoryAPIKeyStore) Create(ctx context.Context, key *domain.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key.ID = uuid.New()
+	key.CreatedAt = *s.now()
+	s.keys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.Hash == hash {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *MemoryAPIKeyStore) List(ctx context.Context) ([]domain.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]domain.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, *cloneAPIKey(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID.String() > keys[j].ID.String()
+	})
+	return keys, nil
+}
+
+func (s *MemoryAPIKeyStore) Rotate(ctx context.Context, id uuid.UUID, hash, prefix string) (*domain.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return nil, ErrNotFound
+	}
+	key.Hash = hash
+	key.Prefix = prefix
+	key.RotatedAt = s.now()
+	key.LastUsedAt = nil
+	return cloneAPIKey(key), nil
+}
+
+func (s *MemoryAPIKeyStore) Revoke(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = s.now()
+	}
+	return nil
+}
+
+func (s *MemoryAPIKeyStore) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[id]; ok {
+		key.LastUsedAt = s.now()
+	}
+	return nil
+}
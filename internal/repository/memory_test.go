@@ -0,0 +1,14 @@
+package repository_test
+
+import (
+	"testing"
+
+	"employees-api/internal/repository"
+	"employees-api/internal/repository/repotest"
+)
+
+func TestMemoryEmployeeStore(t *testing.T) {
+	repotest.RunEmployeeStoreContract(t, func(t *testing.T) repository.EmployeeStore {
+		return repository.NewMemoryEmployeeStore()
+	})
+}
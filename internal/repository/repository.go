@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
+	"math"
+	"strconv"
+	"strings"
 
 	"employees-api/internal/domain"
 
@@ -15,14 +17,16 @@ import (
 )
 
 var (
-	ErrNotFound        = errors.New("запись не найдена")
-	ErrDuplicatePhone  = errors.New("телефон уже существует")
+	ErrNotFound       = errors.New("запись не найдена")
+	ErrDuplicatePhone = errors.New("телефон уже существует")
+	// ErrVersionMismatch возвращается условными изменениями, когда версия записи
+	// отличается от ожидаемой.
+	ErrVersionMismatch = errors.New("версия записи изменилась")
+	// ErrBatchAborted получают элементы пакета, не созданные из-за ошибки
+	// в другом элементе того же атомарного пакета.
+	ErrBatchAborted = errors.New("пакет отменен из-за ошибки в другом элементе")
 )
 
-type contextKey string
-
-const dbTimeKey contextKey = "db_time_ms"
-
 type EmployeeRepository struct {
 	pool *pgxpool.Pool
 }
@@ -31,36 +35,14 @@ func NewEmployeeRepository(pool *pgxpool.Pool) *EmployeeRepository {
 	return &EmployeeRepository{pool: pool}
 }
 
-func setDBTime(ctx context.Context, duration time.Duration) {
-	if v := ctx.Value(dbTimeKey); v != nil {
-		if ptr, ok := v.(*int64); ok {
-			*ptr = duration.Milliseconds()
-		}
-	}
-}
-
-func GetDBTime(ctx context.Context) int64 {
-	if v := ctx.Value(dbTimeKey); v != nil {
-		if ptr, ok := v.(*int64); ok {
-			return *ptr
-		}
-	}
-	return 0
-}
-
-func WithDBTime(ctx context.Context) (context.Context, *int64) {
-	var dbTime int64
-	return context.WithValue(ctx, dbTimeKey, &dbTime), &dbTime
-}
-
 func (r *EmployeeRepository) Create(ctx context.Context, req domain.CreateEmployeeRequest) (*domain.Employee, error) {
 	query := `
+		-- name: employees.create
 		INSERT INTO employees (full_name, phone, city)
 		VALUES ($1, $2, $3)
-		RETURNING id, full_name, phone, city, created_at, updated_at
+		RETURNING id, full_name, phone, city, created_at, updated_at, version
 	`
 
-	start := time.Now()
 	var emp domain.Employee
 	err := r.pool.QueryRow(ctx, query, req.FullName, req.Phone, req.City).Scan(
 		&emp.ID,
@@ -69,9 +51,8 @@ func (r *EmployeeRepository) Create(ctx context.Context, req domain.CreateEmploy
 		&emp.City,
 		&emp.CreatedAt,
 		&emp.UpdatedAt,
+		&emp.Version,
 	)
-	setDBTime(ctx, time.Since(start))
-
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -83,14 +64,83 @@ func (r *EmployeeRepository) Create(ctx context.Context, req domain.CreateEmploy
 	return &emp, nil
 }
 
+// CreateBatch создает сотрудников одной транзакцией. Занятые телефоны не прерывают
+// транзакцию и возвращаются как ErrDuplicatePhone у элемента. В атомарном режиме
+// любой дубль откатывает весь пакет.
+func (r *EmployeeRepository) CreateBatch(ctx context.Context, reqs []domain.CreateEmployeeRequest, atomic bool) ([]domain.BatchCreateResult, error) {
+	query := `
+		-- name: employees.create_batch
+		INSERT INTO employees (full_name, phone, city)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (phone) WHERE deleted_at IS NULL DO NOTHING
+		RETURNING id, full_name, phone, city, created_at, updated_at, version
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка пакетного создания сотрудников: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, req := range reqs {
+		batch.Queue(query, req.FullName, req.Phone, req.City)
+	}
+
+	results := make([]domain.BatchCreateResult, len(reqs))
+	failed := false
+
+	br := tx.SendBatch(ctx, batch)
+	for i := range reqs {
+		var emp domain.Employee
+		err := br.QueryRow().Scan(
+			&emp.ID,
+			&emp.FullName,
+			&emp.Phone,
+			&emp.City,
+			&emp.CreatedAt,
+			&emp.UpdatedAt,
+			&emp.Version,
+		)
+		switch {
+		case err == nil:
+			results[i].Employee = &emp
+		case errors.Is(err, pgx.ErrNoRows):
+			results[i].Err = ErrDuplicatePhone
+			failed = true
+		default:
+			br.Close()
+			return nil, fmt.Errorf("ошибка пакетного создания сотрудников: %w", err)
+		}
+	}
+	if err := br.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка пакетного создания сотрудников: %w", err)
+	}
+
+	if atomic && failed {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = domain.BatchCreateResult{Err: ErrBatchAborted}
+			}
+		}
+		return results, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка пакетного создания сотрудников: %w", err)
+	}
+
+	return results, nil
+}
+
 func (r *EmployeeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
 	query := `
-		SELECT id, full_name, phone, city, created_at, updated_at
+		-- name: employees.get_by_id
+		SELECT id, full_name, phone, city, created_at, updated_at, version
 		FROM employees
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	start := time.Now()
 	var emp domain.Employee
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&emp.ID,
@@ -99,9 +149,8 @@ func (r *EmployeeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 		&emp.City,
 		&emp.CreatedAt,
 		&emp.UpdatedAt,
+		&emp.Version,
 	)
-	setDBTime(ctx, time.Since(start))
-
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -112,6 +161,365 @@ func (r *EmployeeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	return &emp, nil
 }
 
+// GetByIDs возвращает действующих сотрудников из списка ids одним запросом.
+// Ненайденные и удаленные пропускаются, порядок не гарантируется.
+func (r *EmployeeRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Employee, error) {
+	query := `
+		-- name: employees.get_by_ids
+		SELECT id, full_name, phone, city, created_at, updated_at, version
+		FROM employees
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сотрудников: %w", err)
+	}
+
+	employees, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Employee, error) {
+		var emp domain.Employee
+		err := row.Scan(
+			&emp.ID,
+			&emp.FullName,
+			&emp.Phone,
+			&emp.City,
+			&emp.CreatedAt,
+			&emp.UpdatedAt,
+			&emp.Version,
+		)
+		return emp, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения сотрудников: %w", err)
+	}
+
+	return employees, nil
+}
+
+// Update перезаписывает сотрудника, если его версия равна expectedVersion.
+// expectedVersion = 0 означает безусловное обновление.
+func (r *EmployeeRepository) Update(ctx context.Context, id uuid.UUID, expectedVersion int64, req domain.UpdateEmployeeRequest) (*domain.Employee, error) {
+	query := `
+		-- name: employees.update
+		UPDATE employees
+		SET full_name = $2, phone = $3, city = $4, updated_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($5::bigint = 0 OR version = $5)
+		RETURNING id, full_name, phone, city, created_at, updated_at, version
+	`
+
+	var emp domain.Employee
+	err := r.pool.QueryRow(ctx, query, id, req.FullName, req.Phone, req.City, expectedVersion).Scan(
+		&emp.ID,
+		&emp.FullName,
+		&emp.Phone,
+		&emp.City,
+		&emp.CreatedAt,
+		&emp.UpdatedAt,
+		&emp.Version,
+	)
+	if errors.Is(err, pgx.ErrNoRows) && expectedVersion != 0 {
+		return nil, r.conditionFailure(ctx, id)
+	}
+	if err != nil {
+		return nil, mapWriteError(err, "ошибка обновления сотрудника")
+	}
+
+	return &emp, nil
+}
+
+func (r *EmployeeRepository) Patch(ctx context.Context, id uuid.UUID, expectedVersion int64, patch domain.EmployeePatch) (*domain.Employee, error) {
+	query := `
+		-- name: employees.patch
+		UPDATE employees
+		SET full_name = COALESCE($2, full_name),
+			phone = COALESCE($3, phone),
+			city = COALESCE($4, city),
+			updated_at = now(),
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($5::bigint = 0 OR version = $5)
+		RETURNING id, full_name, phone, city, created_at, updated_at, version
+	`
+
+	var emp domain.Employee
+	err := r.pool.QueryRow(ctx, query, id, patch.FullName, patch.Phone, patch.City, expectedVersion).Scan(
+		&emp.ID,
+		&emp.FullName,
+		&emp.Phone,
+		&emp.City,
+		&emp.CreatedAt,
+		&emp.UpdatedAt,
+		&emp.Version,
+	)
+	if errors.Is(err, pgx.ErrNoRows) && expectedVersion != 0 {
+		return nil, r.conditionFailure(ctx, id)
+	}
+	if err != nil {
+		return nil, mapWriteError(err, "ошибка обновления сотрудника")
+	}
+
+	return &emp, nil
+}
+
+// Delete помечает сотрудника удаленным. Телефон удаленного сотрудника
+// освобождается для новых записей.
+func (r *EmployeeRepository) Delete(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
+	query := `
+		-- name: employees.delete
+		UPDATE employees
+		SET deleted_at = now(), updated_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint = 0 OR version = $2)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления сотрудника: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if expectedVersion != 0 {
+			return r.conditionFailure(ctx, id)
+		}
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *EmployeeRepository) Restore(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	query := `
+		-- name: employees.restore
+		UPDATE employees
+		SET deleted_at = NULL, updated_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, full_name, phone, city, created_at, updated_at, version
+	`
+
+	var emp domain.Employee
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&emp.ID,
+		&emp.FullName,
+		&emp.Phone,
+		&emp.City,
+		&emp.CreatedAt,
+		&emp.UpdatedAt,
+		&emp.Version,
+	)
+	if err != nil {
+		return nil, mapWriteError(err, "ошибка восстановления сотрудника")
+	}
+
+	return &emp, nil
+}
+
+// Purge удаляет строку сотрудника без возможности восстановления,
+// в том числе ранее помеченную удаленной.
+func (r *EmployeeRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, "-- name: employees.purge\nDELETE FROM employees WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("ошибка окончательного удаления сотрудника: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// FindExistingPhones возвращает телефоны из списка, уже занятые действующими сотрудниками.
+func (r *EmployeeRepository) FindExistingPhones(ctx context.Context, phones []string) ([]string, error) {
+	query := `
+		-- name: employees.find_existing_phones
+		SELECT phone
+		FROM employees
+		WHERE phone = ANY($1) AND deleted_at IS NULL
+	`
+
+	rows, err := r.pool.Query(ctx, query, phones)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки телефонов: %w", err)
+	}
+
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки телефонов: %w", err)
+	}
+
+	return existing, nil
+}
+
+// conditionFailure определяет, почему условное изменение не затронуло строк:
+// записи нет или ее версия изменилась.
+func (r *EmployeeRepository) conditionFailure(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	err := r.pool.QueryRow(ctx, "-- name: employees.condition_failure\nSELECT EXISTS(SELECT 1 FROM employees WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("ошибка проверки версии сотрудника: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrVersionMismatch
+}
+
+func mapWriteError(err error, msg string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrDuplicatePhone
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
 func (r *EmployeeRepository) HealthCheck(ctx context.Context) error {
 	return r.pool.Ping(ctx)
 }
+
+func (r *EmployeeRepository) List(ctx context.Context, q domain.ListEmployeesQuery) ([]domain.Employee, error) {
+	employees := make([]domain.Employee, 0, q.Limit)
+	err := r.Stream(ctx, q, func(emp *domain.Employee) error {
+		employees = append(employees, *emp)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения списка сотрудников: %w", err)
+	}
+
+	return employees, nil
+}
+
+// Stream передает сотрудников в fn по мере чтения из курсора pgx, не накапливая
+// выборку в памяти. q.Limit = 0 снимает ограничение на количество строк.
+func (r *EmployeeRepository) Stream(ctx context.Context, q domain.ListEmployeesQuery, fn func(*domain.Employee) error) error {
+	query, args := buildListQuery(q)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ошибка чтения сотрудников: %w", err)
+	}
+	defer rows.Close()
+
+	var emp domain.Employee
+	for rows.Next() {
+		if err := rows.Scan(
+			&emp.ID,
+			&emp.FullName,
+			&emp.Phone,
+			&emp.City,
+			&emp.CreatedAt,
+			&emp.UpdatedAt,
+			&emp.Version,
+		); err != nil {
+			return fmt.Errorf("ошибка чтения сотрудника: %w", err)
+		}
+		if err := fn(&emp); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения сотрудников: %w", err)
+	}
+
+	return nil
+}
+
+func buildListQuery(q domain.ListEmployeesQuery) (string, []interface{}) {
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+	)
+
+	addArg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.Filter.City != "" {
+		conditions = append(conditions, "city = "+addArg(q.Filter.City))
+	}
+	if q.Filter.PhonePrefix != "" {
+		conditions = append(conditions, "phone LIKE "+addArg(escapeLike(q.Filter.PhonePrefix)+"%"))
+	}
+
+	order := "ASC"
+	cmp := ">"
+	if q.Sort == domain.SortCreatedAtDesc {
+		order = "DESC"
+		cmp = "<"
+	}
+
+	if q.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) %s (%s, %s)", cmp, addArg(q.After.CreatedAt), addArg(q.After.ID)))
+	}
+
+	query := "-- name: employees.stream\nSELECT id, full_name, phone, city, created_at, updated_at, version FROM employees WHERE " +
+		strings.Join(conditions, " AND ")
+	query += fmt.Sprintf(" ORDER BY created_at %s, id %s", order, order)
+	if q.Limit > 0 {
+		query += " LIMIT " + addArg(q.Limit)
+	}
+
+	return query, args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (r *EmployeeRepository) Search(ctx context.Context, query string, threshold float64, limit int) ([]domain.EmployeeSearchHit, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска сотрудников: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Оператор % использует GIN trgm индекс, порог задается только через настройку сессии.
+	_, err = tx.Exec(ctx, "-- name: employees.search_threshold\nSELECT set_config('pg_trgm.similarity_threshold', $1, true)",
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка установки порога поиска: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		-- name: employees.search
+		SELECT id, full_name, phone, city, created_at, updated_at, version, similarity(full_name, $1) AS score
+		FROM employees
+		WHERE full_name % $1 AND deleted_at IS NULL
+		ORDER BY score DESC, id
+		LIMIT $2
+	`, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска сотрудников: %w", err)
+	}
+	defer rows.Close()
+
+	hits := make([]domain.EmployeeSearchHit, 0, limit)
+	for rows.Next() {
+		var hit domain.EmployeeSearchHit
+		var score float32
+		if err := rows.Scan(
+			&hit.ID,
+			&hit.FullName,
+			&hit.Phone,
+			&hit.City,
+			&hit.CreatedAt,
+			&hit.UpdatedAt,
+			&hit.Version,
+			&score,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения сотрудника: %w", err)
+		}
+		hit.Score = math.Round(float64(score)*10000) / 10000
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка поиска сотрудников: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка поиска сотрудников: %w", err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,324 @@
+// Package repotest содержит контрактные тесты для реализаций repository.EmployeeStore.
+// Один и тот же набор запускается против PostgreSQL и хранилища в памяти,
+// чтобы тесты сервисного слоя на памяти проверяли то же поведение, что и в продакшене.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunEmployeeStoreContract запускает контрактные тесты. newStore вызывается
+// для каждого подтеста и должен возвращать пустое хранилище.
+func RunEmployeeStoreContract(t *testing.T, newStore func(t *testing.T) repository.EmployeeStore) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store repository.EmployeeStore)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"CreateDuplicatePhone", testCreateDuplicatePhone},
+		{"GetNotFound", testGetNotFound},
+		{"GetByIDs", testGetByIDs},
+		{"CreateBatch", testCreateBatch},
+		{"Update", testUpdate},
+		{"Patch", testPatch},
+		{"DeleteRestorePurge", testDeleteRestorePurge},
+		{"FindExistingPhones", testFindExistingPhones},
+		{"List", testList},
+		{"Search", testSearch},
+		{"HealthCheck", testHealthCheck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+func create(t *testing.T, store repository.EmployeeStore, fullName, phone, city string) *domain.Employee {
+	t.Helper()
+	emp, err := store.Create(context.Background(), domain.CreateEmployeeRequest{
+		FullName: fullName,
+		Phone:    phone,
+		City:     city,
+	})
+	require.NoError(t, err)
+	return emp
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func testCreateAndGet(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+
+	created := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+	assert.NotEqual(t, uuid.Nil, created.ID)
+	assert.Equal(t, int64(1), created.Version)
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.True(t, created.CreatedAt.Equal(created.UpdatedAt))
+
+	found, err := store.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	assert.Equal(t, "Иван Иванов", found.FullName)
+	assert.Equal(t, "+77010000001", found.Phone)
+	assert.Equal(t, "Алматы", found.City)
+	assert.True(t, created.CreatedAt.Equal(found.CreatedAt))
+	assert.Equal(t, created.Version, found.Version)
+}
+
+func testCreateDuplicatePhone(t *testing.T, store repository.EmployeeStore) {
+	create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+
+	_, err := store.Create(context.Background(), domain.CreateEmployeeRequest{
+		FullName: "Петр Петров",
+		Phone:    "+77010000001",
+		City:     "Астана",
+	})
+	assert.ErrorIs(t, err, repository.ErrDuplicatePhone)
+}
+
+func testGetNotFound(t *testing.T, store repository.EmployeeStore) {
+	_, err := store.GetByID(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testGetByIDs(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	first := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+	second := create(t, store, "Петр Петров", "+77010000002", "Астана")
+	deleted := create(t, store, "Анна Смирнова", "+77010000003", "Алматы")
+	require.NoError(t, store.Delete(ctx, deleted.ID, 0))
+
+	found, err := store.GetByIDs(ctx, []uuid.UUID{second.ID, uuid.New(), first.ID, deleted.ID, first.ID})
+	require.NoError(t, err)
+
+	byID := make(map[uuid.UUID]domain.Employee, len(found))
+	for _, emp := range found {
+		byID[emp.ID] = emp
+	}
+	assert.Len(t, found, 2, "ненайденные, удаленные и повторы пропускаются")
+	assert.Equal(t, "Иван Иванов", byID[first.ID].FullName)
+	assert.Equal(t, second.Version, byID[second.ID].Version)
+
+	found, err = store.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func testCreateBatch(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	existing := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+
+	reqs := []domain.CreateEmployeeRequest{
+		{FullName: "Анна Смирнова", Phone: "+77010000002", City: "Алматы"},
+		{FullName: "Петр Петров", Phone: existing.Phone, City: "Алматы"},
+		{FullName: "Ольга Ли", Phone: "+77010000002", City: "Алматы"},
+	}
+
+	results, err := store.CreateBatch(ctx, reqs, true)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.ErrorIs(t, results[0].Err, repository.ErrBatchAborted)
+	assert.ErrorIs(t, results[1].Err, repository.ErrDuplicatePhone)
+	assert.ErrorIs(t, results[2].Err, repository.ErrDuplicatePhone)
+
+	phones, err := store.FindExistingPhones(ctx, []string{"+77010000002"})
+	require.NoError(t, err)
+	assert.Empty(t, phones, "атомарный пакет с ошибкой не должен ничего создавать")
+
+	results, err = store.CreateBatch(ctx, reqs, false)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "+77010000002", results[0].Employee.Phone)
+	assert.ErrorIs(t, results[1].Err, repository.ErrDuplicatePhone)
+	assert.ErrorIs(t, results[2].Err, repository.ErrDuplicatePhone)
+
+	_, err = store.GetByID(ctx, results[0].Employee.ID)
+	assert.NoError(t, err)
+}
+
+func testUpdate(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	emp := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+	other := create(t, store, "Петр Петров", "+77010000002", "Алматы")
+
+	req := domain.UpdateEmployeeRequest{FullName: "Иван Петров", Phone: "+77010000003", City: "Астана"}
+
+	_, err := store.Update(ctx, emp.ID, emp.Version+1, req)
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+	_, err = store.Update(ctx, uuid.New(), 1, req)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = store.Update(ctx, uuid.New(), 0, req)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = store.Update(ctx, emp.ID, emp.Version, domain.UpdateEmployeeRequest{
+		FullName: "Иван Петров", Phone: other.Phone, City: "Астана",
+	})
+	assert.ErrorIs(t, err, repository.ErrDuplicatePhone)
+
+	updated, err := store.Update(ctx, emp.ID, emp.Version, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Петров", updated.FullName)
+	assert.Equal(t, "+77010000003", updated.Phone)
+	assert.Equal(t, "Астана", updated.City)
+	assert.Equal(t, emp.Version+1, updated.Version)
+	assert.True(t, emp.CreatedAt.Equal(updated.CreatedAt))
+	assert.False(t, updated.UpdatedAt.Before(emp.UpdatedAt))
+
+	unconditional, err := store.Update(ctx, emp.ID, 0, req)
+	require.NoError(t, err)
+	assert.Equal(t, updated.Version+1, unconditional.Version)
+}
+
+func testPatch(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	emp := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+	other := create(t, store, "Петр Петров", "+77010000002", "Алматы")
+
+	patched, err := store.Patch(ctx, emp.ID, emp.Version, domain.EmployeePatch{City: stringPtr("Астана")})
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Иванов", patched.FullName)
+	assert.Equal(t, "+77010000001", patched.Phone)
+	assert.Equal(t, "Астана", patched.City)
+	assert.Equal(t, emp.Version+1, patched.Version)
+
+	_, err = store.Patch(ctx, emp.ID, emp.Version, domain.EmployeePatch{City: stringPtr("Шымкент")})
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+
+	_, err = store.Patch(ctx, emp.ID, patched.Version, domain.EmployeePatch{Phone: stringPtr(other.Phone)})
+	assert.ErrorIs(t, err, repository.ErrDuplicatePhone)
+
+	_, err = store.Patch(ctx, uuid.New(), 0, domain.EmployeePatch{City: stringPtr("Шымкент")})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testDeleteRestorePurge(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	emp := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+
+	assert.ErrorIs(t, store.Delete(ctx, emp.ID, emp.Version+1), repository.ErrVersionMismatch)
+	require.NoError(t, store.Delete(ctx, emp.ID, emp.Version))
+	assert.ErrorIs(t, store.Delete(ctx, emp.ID, 0), repository.ErrNotFound)
+
+	_, err := store.GetByID(ctx, emp.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = store.Restore(ctx, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// Телефон удаленного сотрудника свободен, пока запись не восстановлена.
+	replacement := create(t, store, "Петр Петров", emp.Phone, "Алматы")
+	_, err = store.Restore(ctx, emp.ID)
+	assert.ErrorIs(t, err, repository.ErrDuplicatePhone)
+
+	require.NoError(t, store.Purge(ctx, replacement.ID))
+	restored, err := store.Restore(ctx, emp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, emp.Phone, restored.Phone)
+	assert.Equal(t, emp.Version+2, restored.Version)
+
+	_, err = store.Restore(ctx, emp.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	require.NoError(t, store.Purge(ctx, emp.ID))
+	assert.ErrorIs(t, store.Purge(ctx, emp.ID), repository.ErrNotFound)
+	_, err = store.Restore(ctx, emp.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testFindExistingPhones(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+	deleted := create(t, store, "Петр Петров", "+77010000002", "Алматы")
+	require.NoError(t, store.Delete(ctx, deleted.ID, 0))
+
+	phones, err := store.FindExistingPhones(ctx, []string{"+77010000001", "+77010000002", "+77010000003"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"+77010000001"}, phones)
+}
+
+func testList(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+
+	var created []*domain.Employee
+	for _, phone := range []string{"+77010000001", "+77010000002", "+77010000003", "+77020000004"} {
+		created = append(created, create(t, store, "Сотрудник", phone, "Алматы"))
+		// Разные created_at делают порядок детерминированным для обеих реализаций.
+		time.Sleep(2 * time.Millisecond)
+	}
+	create(t, store, "Сотрудник", "+77010000005", "Астана")
+	require.NoError(t, store.Delete(ctx, created[1].ID, 0))
+
+	filter := domain.EmployeeFilter{City: "Алматы", PhonePrefix: "+7701"}
+
+	page, err := store.List(ctx, domain.ListEmployeesQuery{Filter: filter, Sort: domain.SortCreatedAtAsc, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, created[0].ID, page[0].ID)
+
+	page, err = store.List(ctx, domain.ListEmployeesQuery{
+		Filter: filter,
+		Sort:   domain.SortCreatedAtAsc,
+		After:  &domain.EmployeeCursor{CreatedAt: page[0].CreatedAt, ID: page[0].ID},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, created[2].ID, page[0].ID)
+
+	var streamed []uuid.UUID
+	err = store.Stream(ctx, domain.ListEmployeesQuery{
+		Filter: domain.EmployeeFilter{City: "Алматы"},
+		Sort:   domain.SortCreatedAtDesc,
+	}, func(emp *domain.Employee) error {
+		streamed = append(streamed, emp.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{created[3].ID, created[2].ID, created[0].ID}, streamed)
+
+	page, err = store.List(ctx, domain.ListEmployeesQuery{
+		Filter: domain.EmployeeFilter{PhonePrefix: "+7701_"},
+		Sort:   domain.SortCreatedAtAsc,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, page, "спецсимволы LIKE в префиксе должны сравниваться буквально")
+}
+
+func testSearch(t *testing.T, store repository.EmployeeStore) {
+	ctx := context.Background()
+	ivanov := create(t, store, "Иван Иванов", "+77010000001", "Алматы")
+	create(t, store, "Айгерим Нурланова", "+77010000002", "Алматы")
+	deleted := create(t, store, "Иван Иванович", "+77010000003", "Алматы")
+	require.NoError(t, store.Delete(ctx, deleted.ID, 0))
+
+	hits, err := store.Search(ctx, "Иваноф", 0.3, 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, ivanov.ID, hits[0].ID)
+	assert.InDelta(t, 0.5, hits[0].Score, 0.0001)
+
+	hits, err = store.Search(ctx, "Иваноф", 0.9, 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+}
+
+func testHealthCheck(t *testing.T, store repository.EmployeeStore) {
+	assert.NoError(t, store.HealthCheck(context.Background()))
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"employees-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// EmployeeStore - хранилище сотрудников, от которого зависит сервисный слой.
+// Реализации обязаны возвращать ErrNotFound, ErrDuplicatePhone, ErrVersionMismatch
+// и ErrBatchAborted в тех же ситуациях, что и EmployeeRepository; поведение
+// закреплено контрактными тестами в пакете repotest.
+type EmployeeStore interface {
+	Create(ctx context.Context, req domain.CreateEmployeeRequest) (*domain.Employee, error)
+	CreateBatch(ctx context.Context, reqs []domain.CreateEmployeeRequest, atomic bool) ([]domain.BatchCreateResult, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Employee, error)
+	Update(ctx context.Context, id uuid.UUID, expectedVersion int64, req domain.UpdateEmployeeRequest) (*domain.Employee, error)
+	Patch(ctx context.Context, id uuid.UUID, expectedVersion int64, patch domain.EmployeePatch) (*domain.Employee, error)
+	Delete(ctx context.Context, id uuid.UUID, expectedVersion int64) error
+	Restore(ctx context.Context, id uuid.UUID) (*domain.Employee, error)
+	Purge(ctx context.Context, id uuid.UUID) error
+	FindExistingPhones(ctx context.Context, phones []string) ([]string, error)
+	List(ctx context.Context, q domain.ListEmployeesQuery) ([]domain.Employee, error)
+	Stream(ctx context.Context, q domain.ListEmployeesQuery, fn func(*domain.Employee) error) error
+	Search(ctx context.Context, query string, threshold float64, limit int) ([]domain.EmployeeSearchHit, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// APIKeyStore - хранилище API ключей. Rotate и Revoke возвращают ErrNotFound
+// для несуществующего ключа, Rotate - и для отозванного.
+type APIKeyStore interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	GetByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	List(ctx context.Context) ([]domain.APIKey, error)
+	Rotate(ctx context.Context, id uuid.UUID, hash, prefix string) (*domain.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+var (
+	_ EmployeeStore = (*EmployeeRepository)(nil)
+	_ EmployeeStore = (*MemoryEmployeeStore)(nil)
+	_ APIKeyStore   = (*APIKeyRepository)(nil)
+	_ APIKeyStore   = (*MemoryAPIKeyStore)(nil)
+)
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"employees-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Employees — операции над сотрудниками, которые вызывает транспортный слой.
+// Реализуется EmployeeService и обертками над ним, например проверкой прав.
+type Employees interface {
+	CreateEmployee(ctx context.Context, req domain.CreateEmployeeRequest) (*domain.Employee, error)
+	UpdateEmployee(ctx context.Context, id uuid.UUID, expectedVersion int64, req domain.UpdateEmployeeRequest) (*domain.Employee, error)
+	PatchEmployee(ctx context.Context, id uuid.UUID, expectedVersion int64, patch domain.EmployeePatch) (*domain.Employee, error)
+	CreateEmployeesBatch(ctx context.Context, req domain.BatchCreateEmployeesRequest) ([]domain.BatchCreateResult, error)
+	GetEmployeeByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error)
+	GetEmployeesByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Employee, error)
+	DeleteEmployee(ctx context.Context, id uuid.UUID, expectedVersion int64) error
+	RestoreEmployee(ctx context.Context, id uuid.UUID) (*domain.Employee, error)
+	PurgeEmployee(ctx context.Context, id uuid.UUID) error
+	ListEmployees(ctx context.Context, req domain.ListEmployeesRequest) (*domain.EmployeeList, error)
+	ExportEmployees(ctx context.Context, req domain.ExportEmployeesRequest, fn func(*domain.Employee) error) error
+	SearchEmployees(ctx context.Context, req domain.SearchEmployeesRequest) (*domain.EmployeeSearchResult, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// Imports — операции импорта, которые вызывает транспортный слой.
+type Imports interface {
+	StartImport(ctx context.Context, req domain.ImportRequest) (*domain.ImportJob, error)
+	GetImportJob(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error)
+	GetImportReport(ctx context.Context, id uuid.UUID) ([]domain.ImportRowError, error)
+}
+
+// APIKeys — управление API ключами и их проверка. AuthenticateAPIKey
+// вызывается до аутентификации клиента, поэтому обертки не проверяют для нее права.
+type APIKeys interface {
+	CreateAPIKey(ctx context.Context, req domain.CreateAPIKeyRequest) (*domain.IssuedAPIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RotateAPIKey(ctx context.Context, id uuid.UUID) (*domain.IssuedAPIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	AuthenticateAPIKey(ctx context.Context, plaintext string) (*domain.APIKey, error)
+}
+
+var (
+	_ Employees = (*EmployeeService)(nil)
+	_ Imports   = (*ImportService)(nil)
+	_ APIKeys   = (*APIKeyService)(nil)
+)
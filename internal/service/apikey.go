@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// APIKeyPrefix начинает каждый ключ, чтобы его было легко найти в
+	// конфигурации клиента и сканерах секретов.
+	APIKeyPrefix = "eak_"
+
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+	maxAPIKeyScopes     = 20
+
+	// lastUsedPrecision — точность last_used_at: чаще ключ не обновляется,
+	// чтобы пакетные задачи не писали в БД на каждый запрос.
+	lastUsedPrecision = time.Minute
+)
+
+var ErrInvalidAPIKey = errors.New("невалидный API ключ")
+
+// Ключ может получить только действия над сотрудниками и импортом: права на
+// управление ключами через API ключ не выдаются. Существование действия
+// проверяет authz.APIKeyService.
+var apiKeyScopeRegex = regexp.MustCompile(`^(employees|imports)\.(\*|[a-z]+)$`)
+
+// APIKeyService выпускает и проверяет API ключи сервисных клиентов.
+type APIKeyService struct {
+	store repository.APIKeyStore
+	now   func() time.Time
+}
+
+func NewAPIKeyService(store repository.APIKeyStore) *APIKeyService {
+	return &APIKeyService{store: store, now: time.Now}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, req domain.CreateAPIKeyRequest) (*domain.IssuedAPIKey, error) {
+	ctx, span := startSpan(ctx, "APIKeyService.CreateAPIKey")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+
+	name := NormalizeString(req.Name)
+	if length := utf8.RuneCountInString(name); length == 0 || length > 100 {
+		validationErrs.Add("name", i18n.LengthRange, i18n.Params{"min": 1, "max": 100})
+	}
+
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		validationErrs.AddError("scopes", err)
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.now()) {
+		validationErrs.Add("expiresAt", i18n.FutureTime, nil)
+	}
+
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
+	}
+
+	plaintext, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &domain.APIKey{
+		Name:      name,
+		Prefix:    plaintext[:apiKeyDisplayLength],
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+		Hash:      hash,
+	}
+	if err := s.store.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &domain.IssuedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	ctx, span := startSpan(ctx, "APIKeyService.ListAPIKeys")
+	defer span.End()
+
+	return s.store.List(ctx)
+}
+
+// RotateAPIKey выпускает новое значение ключа с теми же именем, правами и
+// сроком действия. Прежнее значение перестает приниматься сразу.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id uuid.UUID) (*domain.IssuedAPIKey, error) {
+	ctx, span := startSpan(ctx, "APIKeyService.RotateAPIKey")
+	defer span.End()
+
+	plaintext, hash, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.store.Rotate(ctx, id, hash, plaintext[:apiKeyDisplayLength])
+	if err != nil {
+		return nil, err
+	}
+	return &domain.IssuedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "APIKeyService.RevokeAPIKey")
+	defer span.End()
+
+	return s.store.Revoke(ctx, id)
+}
+
+// AuthenticateAPIKey возвращает действующий ключ по открытому значению.
+// Неизвестный, отозванный и просроченный ключи дают ErrInvalidAPIKey.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, plaintext string) (*domain.APIKey, error) {
+	ctx, span := startSpan(ctx, "APIKeyService.AuthenticateAPIKey")
+	defer span.End()
+
+	if !strings.HasPrefix(plaintext, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.store.GetByHash(ctx, hashAPIKey(plaintext))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := s.now()
+	if !key.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedPrecision {
+		if err := s.store.TouchLastUsed(ctx, key.ID); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, violation(i18n.MinItems, i18n.Params{"min": 1})
+	}
+	if len(scopes) > maxAPIKeyScopes {
+		return nil, violation(i18n.MaxItems, i18n.Params{"max": maxAPIKeyScopes})
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !apiKeyScopeRegex.MatchString(scope) {
+			return nil, violation(i18n.APIKeyScope, i18n.Params{"value": scope})
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+// generateAPIKey возвращает новый ключ из 256 случайных бит и его хеш.
+// Энтропии ключа достаточно, чтобы хранить быстрый SHA-256 без соли.
+func generateAPIKey() (plaintext, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("ошибка генерации API ключа: %w", err)
+	}
+	plaintext = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return plaintext, hashAPIKey(plaintext), nil
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
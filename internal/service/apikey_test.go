@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService_Lifecycle(t *testing.T) {
+	store := repository.NewMemoryAPIKeyStore()
+	svc := NewAPIKeyService(store)
+	ctx := context.Background()
+
+	issued, err := svc.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{
+		Name:   " payroll ",
+		Scopes: []string{"employees.read", "employees.list", "employees.read"},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(issued.Key, APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(issued.Key, issued.Prefix))
+	assert.Equal(t, "payroll", issued.Name)
+	assert.Equal(t, []string{"employees.read", "employees.list"}, issued.Scopes)
+
+	keys, err := svc.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotContains(t, keys[0].Hash, issued.Key, "открытое значение не хранится")
+	assert.Nil(t, keys[0].LastUsedAt)
+
+	key, err := svc.AuthenticateAPIKey(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, issued.ID, key.ID)
+
+	keys, err = svc.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, keys[0].LastUsedAt)
+
+	rotated, err := svc.RotateAPIKey(ctx, issued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, issued.ID, rotated.ID)
+	assert.NotEqual(t, issued.Key, rotated.Key)
+	assert.NotNil(t, rotated.RotatedAt)
+
+	_, err = svc.AuthenticateAPIKey(ctx, issued.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey, "прежнее значение не действует после ротации")
+	_, err = svc.AuthenticateAPIKey(ctx, rotated.Key)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RevokeAPIKey(ctx, issued.ID))
+	require.NoError(t, svc.RevokeAPIKey(ctx, issued.ID), "повторный отзыв не ошибка")
+	_, err = svc.AuthenticateAPIKey(ctx, rotated.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = svc.RotateAPIKey(ctx, issued.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "отозванный ключ нельзя ротировать")
+}
+
+func TestAPIKeyService_Expired(t *testing.T) {
+	svc := NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	issued, err := svc.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{Name: "badges", Scopes: []string{"employees.*"}, ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+
+	_, err = svc.AuthenticateAPIKey(ctx, issued.Key)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return expiresAt.Add(time.Second) }
+	_, err = svc.AuthenticateAPIKey(ctx, issued.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_AuthenticateUnknown(t *testing.T) {
+	svc := NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+
+	for _, key := range []string{"", "eak_unknown", "not-a-key"} {
+		_, err := svc.AuthenticateAPIKey(context.Background(), key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, key)
+	}
+}
+
+func TestAPIKeyService_Validation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		req   domain.CreateAPIKeyRequest
+		field string
+	}{
+		{"пустое имя", domain.CreateAPIKeyRequest{Name: " ", Scopes: []string{"employees.read"}}, "name"},
+		{"без действий", domain.CreateAPIKeyRequest{Name: "payroll"}, "scopes"},
+		{"управление ключами", domain.CreateAPIKeyRequest{Name: "payroll", Scopes: []string{"apikeys.create"}}, "scopes"},
+		{"все действия", domain.CreateAPIKeyRequest{Name: "payroll", Scopes: []string{"*"}}, "scopes"},
+		{"срок в прошлом", domain.CreateAPIKeyRequest{Name: "payroll", Scopes: []string{"employees.read"}, ExpiresAt: &past}, "expiresAt"},
+	}
+
+	svc := NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateAPIKey(context.Background(), tt.req)
+			var validationErrs *ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			require.Len(t, validationErrs.Errors, 1)
+			assert.Equal(t, tt.field, validationErrs.Errors[0].Field)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+
+	"github.com/google/uuid"
+)
+
+var errInvalidCursor = violation(i18n.InvalidCursor, nil)
+
+type cursorPayload struct {
+	Sort      domain.EmployeeSort `json:"s"`
+	CreatedAt time.Time           `json:"c"`
+	ID        uuid.UUID           `json:"i"`
+}
+
+func encodeCursor(sort domain.EmployeeSort, emp domain.Employee) string {
+	data, _ := json.Marshal(cursorPayload{
+		Sort:      sort,
+		CreatedAt: emp.CreatedAt,
+		ID:        emp.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(sort domain.EmployeeSort, cursor string) (*domain.EmployeeCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, errInvalidCursor
+	}
+	if payload.Sort != sort || payload.ID == uuid.Nil {
+		return nil, errInvalidCursor
+	}
+
+	return &domain.EmployeeCursor{
+		CreatedAt: payload.CreatedAt,
+		ID:        payload.ID,
+	}, nil
+}
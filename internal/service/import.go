@@ -0,0 +1,454 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+	"employees-api/internal/xlsx"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	MaxImportRows   = 100000
+	importChunkSize = 500
+
+	// finishAttempts - сколько раз сохраняется результат задачи, прежде чем
+	// она останется running до FailStale при следующем запуске.
+	finishAttempts = 3
+)
+
+var ErrImportInProgress = errors.New("импорт еще выполняется")
+
+// ImportService выполняет импорт сотрудников из файлов в фоне. Файл задачи
+// хранится в памяти процесса до ее завершения.
+type ImportService struct {
+	repo    repository.EmployeeStore
+	jobs    *repository.ImportJobRepository
+	timeout time.Duration
+	logger  *slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type ImportOption func(*ImportService)
+
+// WithImportLogger задает логгер ошибок фоновых задач. По умолчанию slog.Default().
+func WithImportLogger(logger *slog.Logger) ImportOption {
+	return func(s *ImportService) {
+		s.logger = logger
+	}
+}
+
+func NewImportService(repo repository.EmployeeStore, jobs *repository.ImportJobRepository, timeout time.Duration, opts ...ImportOption) *ImportService {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &ImportService{
+		repo:    repo,
+		jobs:    jobs,
+		timeout: timeout,
+		logger:  slog.Default(),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *ImportService) StartImport(ctx context.Context, req domain.ImportRequest) (*domain.ImportJob, error) {
+	ctx, span := startSpan(ctx, "ImportService.StartImport")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+
+	if req.Format != domain.ImportFormatCSV && req.Format != domain.ImportFormatXLSX {
+		validationErrs.Add("format", i18n.OneOf, i18n.Params{"values": "csv, xlsx"})
+	}
+	if len(req.Data) == 0 {
+		validationErrs.Add("file", i18n.EmptyFile, nil)
+	}
+
+	req.Mapping.FullName = defaultColumn(req.Mapping.FullName, "fullName")
+	req.Mapping.Phone = defaultColumn(req.Mapping.Phone, "phone")
+	req.Mapping.City = defaultColumn(req.Mapping.City, "city")
+
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
+	}
+
+	job := &domain.ImportJob{
+		Status:  domain.ImportStatusPending,
+		Format:  req.Format,
+		DryRun:  req.DryRun,
+		Mapping: req.Mapping,
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// Задача переживает запрос, поэтому получает свою трассировку со ссылкой на запрос.
+	link := trace.LinkFromContext(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(job, req, link)
+	}()
+
+	return job, nil
+}
+
+func (s *ImportService) GetImportJob(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	ctx, span := startSpan(ctx, "ImportService.GetImportJob")
+	defer span.End()
+
+	return s.jobs.GetByID(ctx, id)
+}
+
+// GetImportReport возвращает ошибки по строкам завершенной задачи.
+func (s *ImportService) GetImportReport(ctx context.Context, id uuid.UUID) ([]domain.ImportRowError, error) {
+	ctx, span := startSpan(ctx, "ImportService.GetImportReport")
+	defer span.End()
+
+	job, err := s.jobs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == domain.ImportStatusPending || job.Status == domain.ImportStatusRunning {
+		return nil, ErrImportInProgress
+	}
+	return s.jobs.GetRowErrors(ctx, id)
+}
+
+// FailStale завершает задачи, брошенные остановленными процессами.
+func (s *ImportService) FailStale(ctx context.Context) (int64, error) {
+	return s.jobs.FailStale(ctx, s.timeout, string(i18n.MsgImportInterrupted))
+}
+
+// Shutdown прерывает выполняющиеся задачи и ждет, пока они сохранят результат.
+func (s *ImportService) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *ImportService) run(job *domain.ImportJob, req domain.ImportRequest, link trace.Link) {
+	ctx, span := startSpan(s.ctx, "ImportService.run", trace.WithNewRoot(), trace.WithLinks(link))
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Задача выполняется вне обработчика HTTP, и паника в разборе файла
+	// остановила бы весь процесс.
+	defer func() {
+		if p := recover(); p != nil {
+			s.logger.ErrorContext(ctx, "паника_в_задаче_импорта",
+				slog.String("import_id", job.ID.String()),
+				slog.Any("panic", p),
+			)
+			job.Status = domain.ImportStatusFailed
+			job.ErrorCode, job.ErrorParams = string(i18n.MsgImportFailed), nil
+			s.finish(ctx, job)
+		}
+	}()
+
+	if err := s.jobs.MarkRunning(ctx, job.ID); err != nil {
+		s.fail(ctx, job, err)
+		s.finish(ctx, job)
+		return
+	}
+
+	if err := s.process(ctx, job, req); err != nil {
+		s.fail(ctx, job, err)
+	} else {
+		job.Status = domain.ImportStatusCompleted
+	}
+
+	sort.SliceStable(job.RowErrors, func(i, j int) bool {
+		return job.RowErrors[i].Row < job.RowErrors[j].Row
+	})
+	s.finish(ctx, job)
+}
+
+// fail завершает задачу с ошибкой. Клиент получает только код сообщения из
+// каталога: текст внутренних ошибок (pgx, SQL) может раскрыть устройство БД и
+// пишется в лог.
+func (s *ImportService) fail(ctx context.Context, job *domain.ImportJob, err error) {
+	job.Status = domain.ImportStatusFailed
+
+	var violation *Violation
+	switch {
+	case errors.As(err, &violation):
+		job.ErrorCode, job.ErrorParams = string(violation.Code), violation.Params
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		job.ErrorCode = string(i18n.MsgImportTimeout)
+	case errors.Is(ctx.Err(), context.Canceled):
+		job.ErrorCode = string(i18n.MsgImportInterrupted)
+	default:
+		s.logger.ErrorContext(ctx, "ошибка_задачи_импорта",
+			slog.String("import_id", job.ID.String()),
+			slog.Any("error", err),
+		)
+		job.ErrorCode = string(i18n.MsgImportFailed)
+	}
+}
+
+// finish сохраняет результат задачи. Каждая попытка получает свой контекст:
+// контекст задачи может быть уже отменен, а таймаут прошлой попытки истек.
+func (s *ImportService) finish(ctx context.Context, job *domain.ImportJob) {
+	ctx = context.WithoutCancel(ctx)
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := s.jobs.Finish(attemptCtx, job)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		s.logger.ErrorContext(ctx, "ошибка_сохранения_результата_импорта",
+			slog.String("import_id", job.ID.String()),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+		if attempt == finishAttempts {
+			return
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+}
+
+type importRow struct {
+	line int
+	req  domain.CreateEmployeeRequest
+}
+
+func (s *ImportService) process(ctx context.Context, job *domain.ImportJob, req domain.ImportRequest) error {
+	rows, err := readImportRows(req.Format, req.Data)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return violation(i18n.MsgImportNoHeader, nil)
+	}
+
+	columns, err := resolveColumns(rows[0], req.Mapping)
+	if err != nil {
+		return err
+	}
+
+	addError := func(line int, field string, code i18n.MessageID, params i18n.Params) {
+		job.RowErrors = append(job.RowErrors, domain.ImportRowError{Row: line, Field: field, Code: string(code), Params: params})
+	}
+
+	var valid []importRow
+	phoneLines := make(map[string]int)
+
+	for i, row := range rows[1:] {
+		line := i + 2
+		if isEmptyRow(row) {
+			continue
+		}
+		job.TotalRows++
+
+		item := domain.CreateEmployeeRequest{
+			FullName: cellAt(row, columns[0]),
+			Phone:    cellAt(row, columns[1]),
+			City:     cellAt(row, columns[2]),
+		}
+
+		rowErrs := &ValidationErrors{}
+		validateEmployeeFields(ctx, rowErrs, &item.FullName, &item.Phone, &item.City)
+		if !rowErrs.HasErrors() {
+			if first, ok := phoneLines[item.Phone]; ok {
+				rowErrs.Add("phone", i18n.DuplicateInFile, i18n.Params{"row": first})
+			} else {
+				phoneLines[item.Phone] = line
+			}
+		}
+
+		if rowErrs.HasErrors() {
+			for _, e := range rowErrs.Errors {
+				addError(line, e.Field, e.Code, e.Params)
+			}
+			job.FailedRows++
+			continue
+		}
+		valid = append(valid, importRow{line: line, req: item})
+	}
+
+	if req.DryRun {
+		phones := make([]string, len(valid))
+		for i, row := range valid {
+			phones[i] = row.req.Phone
+		}
+
+		existing, err := s.repo.FindExistingPhones(ctx, phones)
+		if err != nil {
+			return err
+		}
+		taken := make(map[string]bool, len(existing))
+		for _, phone := range existing {
+			taken[phone] = true
+		}
+
+		for _, row := range valid {
+			if taken[row.req.Phone] {
+				addError(row.line, "phone", i18n.MsgDuplicatePhone, nil)
+				job.FailedRows++
+				continue
+			}
+			job.ValidRows++
+		}
+		return nil
+	}
+
+	for start := 0; start < len(valid); start += importChunkSize {
+		chunk := valid[start:min(start+importChunkSize, len(valid))]
+
+		reqs := make([]domain.CreateEmployeeRequest, len(chunk))
+		for i, row := range chunk {
+			reqs[i] = row.req
+		}
+
+		results, err := s.repo.CreateBatch(ctx, reqs, false)
+		if err != nil {
+			return fmt.Errorf("импорт прерван на строке %d: %w", chunk[0].line, err)
+		}
+
+		for i, result := range results {
+			if errors.Is(result.Err, repository.ErrDuplicatePhone) {
+				addError(chunk[i].line, "phone", i18n.MsgDuplicatePhone, nil)
+				job.FailedRows++
+				continue
+			}
+			// С atomic=false строка может не создаться только из-за дубля,
+			// остальное - сбой, а не ошибка в данных строки.
+			if result.Err != nil {
+				return fmt.Errorf("импорт прерван на строке %d: %w", chunk[i].line, result.Err)
+			}
+			job.ValidRows++
+			job.CreatedRows++
+		}
+	}
+
+	return nil
+}
+
+func readImportRows(format domain.ImportFormat, data []byte) ([][]string, error) {
+	if format == domain.ImportFormatXLSX {
+		rows, err := xlsx.ReadRows(bytes.NewReader(data), int64(len(data)), MaxImportRows+1)
+		switch {
+		case errors.Is(err, xlsx.ErrTooManyRows):
+			return nil, violation(i18n.MsgImportTooManyRows, i18n.Params{"max": MaxImportRows})
+		case errors.Is(err, xlsx.ErrTooLarge):
+			return nil, violation(i18n.MsgFileTooLarge, nil)
+		case err != nil:
+			return nil, fmt.Errorf("%w: %w", violation(i18n.MsgImportInvalidFile, i18n.Params{"format": "XLSX"}), err)
+		}
+		return rows, nil
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.Comma = detectCSVDelimiter(data)
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", violation(i18n.MsgImportInvalidFile, i18n.Params{"format": "CSV"}), err)
+		}
+		if len(rows) > MaxImportRows {
+			return nil, violation(i18n.MsgImportTooManyRows, i18n.Params{"max": MaxImportRows})
+		}
+		rows = append(rows, record)
+	}
+}
+
+// detectCSVDelimiter выбирает разделитель по заголовку: русская локаль Excel
+// сохраняет CSV с точкой с запятой.
+func detectCSVDelimiter(data []byte) rune {
+	header := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		header = data[:i]
+	}
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func resolveColumns(header []string, mapping domain.ImportColumnMapping) ([3]int, error) {
+	var columns [3]int
+	for i, name := range []string{mapping.FullName, mapping.Phone, mapping.City} {
+		columns[i] = -1
+		for j, title := range header {
+			if strings.EqualFold(strings.TrimSpace(title), strings.TrimSpace(name)) {
+				columns[i] = j
+				break
+			}
+		}
+		if columns[i] < 0 {
+			return columns, violation(i18n.MsgImportMissingColumn, i18n.Params{"column": name})
+		}
+	}
+	return columns, nil
+}
+
+func defaultColumn(column, field string) string {
+	if strings.TrimSpace(column) == "" {
+		return field
+	}
+	return column
+}
+
+func cellAt(row []string, idx int) string {
+	if idx < len(row) {
+		return row[idx]
+	}
+	return ""
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadImportRows_CSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "запятая",
+			data: "ФИО,Телефон,Город\nИван Иванов,+79991234567,Москва\n",
+		},
+		{
+			name: "точка с запятой и BOM",
+			data: "\xef\xbb\xbfФИО;Телефон;Город\r\nИван Иванов;+79991234567;Москва\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readImportRows(domain.ImportFormatCSV, []byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{
+				{"ФИО", "Телефон", "Город"},
+				{"Иван Иванов", "+79991234567", "Москва"},
+			}, rows)
+		})
+	}
+}
+
+func TestResolveColumns(t *testing.T) {
+	header := []string{"Город", " фио ", "Телефон"}
+
+	columns, err := resolveColumns(header, domain.ImportColumnMapping{
+		FullName: "ФИО",
+		Phone:    "Телефон",
+		City:     "Город",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [3]int{1, 2, 0}, columns)
+
+	_, err = resolveColumns(header, domain.ImportColumnMapping{
+		FullName: "ФИО",
+		Phone:    "Мобильный",
+		City:     "Город",
+	})
+	var v *Violation
+	require.ErrorAs(t, err, &v)
+	assert.Equal(t, &Violation{Code: i18n.MsgImportMissingColumn, Params: i18n.Params{"column": "Мобильный"}}, v)
+}
+
+func TestReadImportRows_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format domain.ImportFormat
+		data   string
+		want   *Violation
+	}{
+		{"слишком много строк", domain.ImportFormatCSV, strings.Repeat("a\n", MaxImportRows+2), &Violation{Code: i18n.MsgImportTooManyRows, Params: i18n.Params{"max": MaxImportRows}}},
+		{"не XLSX", domain.ImportFormatXLSX, "fullName,phone,city\n", &Violation{Code: i18n.MsgImportInvalidFile, Params: i18n.Params{"format": "XLSX"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readImportRows(tt.format, []byte(tt.data))
+			var v *Violation
+			require.ErrorAs(t, err, &v)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
@@ -4,48 +4,343 @@ import (
 	"context"
 
 	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/redact"
 	"employees-api/internal/repository"
 
 	"github.com/google/uuid"
 )
 
 type EmployeeService struct {
-	repo *repository.EmployeeRepository
+	repo            repository.EmployeeStore
+	searchThreshold float64
 }
 
-func NewEmployeeService(repo *repository.EmployeeRepository) *EmployeeService {
-	return &EmployeeService{repo: repo}
+type Option func(*EmployeeService)
+
+func WithSearchThreshold(threshold float64) Option {
+	return func(s *EmployeeService) {
+		s.searchThreshold = threshold
+	}
+}
+
+func NewEmployeeService(repo repository.EmployeeStore, opts ...Option) *EmployeeService {
+	s := &EmployeeService{
+		repo:            repo,
+		searchThreshold: DefaultSearchThreshold,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *EmployeeService) CreateEmployee(ctx context.Context, req domain.CreateEmployeeRequest) (*domain.Employee, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.CreateEmployee")
+	defer span.End()
+
 	validationErrs := &ValidationErrors{}
+	validateEmployeeFields(ctx, validationErrs, &req.FullName, &req.Phone, &req.City)
 
-	req.FullName = NormalizeString(req.FullName)
-	if err := ValidateFullName(req.FullName); err != nil {
-		validationErrs.Add("fullName", err.Error())
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
 	}
 
-	req.Phone = NormalizeString(req.Phone)
-	if err := ValidatePhone(req.Phone); err != nil {
-		validationErrs.Add("phone", err.Error())
+	return s.repo.Create(ctx, req)
+}
+
+// UpdateEmployee заменяет все поля сотрудника. expectedVersion = 0 отключает
+// проверку версии.
+func (s *EmployeeService) UpdateEmployee(ctx context.Context, id uuid.UUID, expectedVersion int64, req domain.UpdateEmployeeRequest) (*domain.Employee, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.UpdateEmployee")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+	validateEmployeeFields(ctx, validationErrs, &req.FullName, &req.Phone, &req.City)
+
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
 	}
 
-	req.City = NormalizeString(req.City)
-	if err := ValidateCity(req.City); err != nil {
-		validationErrs.Add("city", err.Error())
+	return s.repo.Update(ctx, id, expectedVersion, req)
+}
+
+func (s *EmployeeService) PatchEmployee(ctx context.Context, id uuid.UUID, expectedVersion int64, patch domain.EmployeePatch) (*domain.Employee, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.PatchEmployee")
+	defer span.End()
+
+	if patch.IsEmpty() {
+		emp, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if expectedVersion != 0 && emp.Version != expectedVersion {
+			return nil, repository.ErrVersionMismatch
+		}
+		return emp, nil
 	}
 
+	validationErrs := &ValidationErrors{}
+	validateEmployeeFields(ctx, validationErrs, patch.FullName, patch.Phone, patch.City)
+
 	if validationErrs.HasErrors() {
 		return nil, validationErrs
 	}
 
-	return s.repo.Create(ctx, req)
+	return s.repo.Patch(ctx, id, expectedVersion, patch)
+}
+
+// validateEmployeeFields нормализует и проверяет переданные поля, nil означает
+// что поле не изменяется. ФИО и телефон запоминаются для маскирования в логах.
+func validateEmployeeFields(ctx context.Context, validationErrs *ValidationErrors, fullName, phone, city *string) {
+	if fullName != nil {
+		*fullName = NormalizeString(*fullName)
+		redact.RememberName(ctx, *fullName)
+		if err := ValidateFullName(*fullName); err != nil {
+			validationErrs.AddError("fullName", err)
+		}
+	}
+
+	if phone != nil {
+		*phone = NormalizeString(*phone)
+		redact.RememberPhone(ctx, *phone)
+		if err := ValidatePhone(*phone); err != nil {
+			validationErrs.AddError("phone", err)
+		}
+	}
+
+	if city != nil {
+		*city = NormalizeString(*city)
+		if err := ValidateCity(*city); err != nil {
+			validationErrs.AddError("city", err)
+		}
+	}
+}
+
+// CreateEmployeesBatch проверяет каждый элемент как CreateEmployee и создает
+// валидные. Ошибки элементов возвращаются в результатах, ошибка функции означает
+// невалидный запрос целиком или сбой БД.
+func (s *EmployeeService) CreateEmployeesBatch(ctx context.Context, req domain.BatchCreateEmployeesRequest) ([]domain.BatchCreateResult, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.CreateEmployeesBatch")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+
+	if req.Mode == "" {
+		req.Mode = domain.BatchModeAtomic
+	}
+	if req.Mode != domain.BatchModeAtomic && req.Mode != domain.BatchModeBestEffort {
+		validationErrs.Add("mode", i18n.OneOf, i18n.Params{"values": "atomic, bestEffort"})
+	}
+	if len(req.Items) == 0 || len(req.Items) > MaxBatchSize {
+		validationErrs.Add("items", i18n.ItemCount, i18n.Params{"min": 1, "max": MaxBatchSize})
+	}
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
+	}
+
+	results := make([]domain.BatchCreateResult, len(req.Items))
+	valid := make([]domain.CreateEmployeeRequest, 0, len(req.Items))
+	validIdx := make([]int, 0, len(req.Items))
+
+	for i, item := range req.Items {
+		itemErrs := &ValidationErrors{}
+		validateEmployeeFields(ctx, itemErrs, &item.FullName, &item.Phone, &item.City)
+		if itemErrs.HasErrors() {
+			results[i].Err = itemErrs
+			continue
+		}
+		valid = append(valid, item)
+		validIdx = append(validIdx, i)
+	}
+
+	atomic := req.Mode == domain.BatchModeAtomic
+	if len(valid) == 0 || (atomic && len(valid) < len(req.Items)) {
+		for _, i := range validIdx {
+			results[i].Err = repository.ErrBatchAborted
+		}
+		return results, nil
+	}
+
+	created, err := s.repo.CreateBatch(ctx, valid, atomic)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range validIdx {
+		results[i] = created[j]
+	}
+
+	return results, nil
 }
 
 func (s *EmployeeService) GetEmployeeByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.GetEmployeeByID")
+	defer span.End()
+
 	return s.repo.GetByID(ctx, id)
 }
 
+// GetEmployeesByIDs загружает сотрудников одним запросом. Ненайденные и
+// удаленные пропускаются, порядок не гарантируется.
+func (s *EmployeeService) GetEmployeesByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Employee, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.GetEmployeesByIDs")
+	defer span.End()
+
+	return s.repo.GetByIDs(ctx, ids)
+}
+
+func (s *EmployeeService) DeleteEmployee(ctx context.Context, id uuid.UUID, expectedVersion int64) error {
+	ctx, span := startSpan(ctx, "EmployeeService.DeleteEmployee")
+	defer span.End()
+
+	return s.repo.Delete(ctx, id, expectedVersion)
+}
+
+func (s *EmployeeService) RestoreEmployee(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.RestoreEmployee")
+	defer span.End()
+
+	return s.repo.Restore(ctx, id)
+}
+
+func (s *EmployeeService) PurgeEmployee(ctx context.Context, id uuid.UUID) error {
+	ctx, span := startSpan(ctx, "EmployeeService.PurgeEmployee")
+	defer span.End()
+
+	return s.repo.Purge(ctx, id)
+}
+
+func (s *EmployeeService) ListEmployees(ctx context.Context, req domain.ListEmployeesRequest) (*domain.EmployeeList, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.ListEmployees")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+
+	q := parseListFilter(validationErrs, req.City, req.PhonePrefix, req.Sort)
+	q.Limit = req.Limit
+
+	if q.Limit == 0 {
+		q.Limit = DefaultListLimit
+	}
+	if err := ValidateListLimit(q.Limit); err != nil {
+		validationErrs.AddError("limit", err)
+	}
+
+	if cursor := NormalizeString(req.Cursor); cursor != "" && !validationErrs.HasErrors() {
+		after, err := decodeCursor(q.Sort, cursor)
+		if err != nil {
+			validationErrs.AddError("cursor", err)
+		}
+		q.After = after
+	}
+
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
+	}
+
+	limit := q.Limit
+	q.Limit = limit + 1
+
+	items, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &domain.EmployeeList{Items: items}
+	if len(items) > limit {
+		list.Items = items[:limit]
+		next := encodeCursor(q.Sort, list.Items[limit-1])
+		list.NextCursor = &next
+	}
+
+	return list, nil
+}
+
+// ExportEmployees проверяет фильтры как ListEmployees и передает в fn всех
+// подходящих сотрудников без пагинации. fn не вызывается при ошибке валидации.
+func (s *EmployeeService) ExportEmployees(ctx context.Context, req domain.ExportEmployeesRequest, fn func(*domain.Employee) error) error {
+	ctx, span := startSpan(ctx, "EmployeeService.ExportEmployees")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+
+	q := parseListFilter(validationErrs, req.City, req.PhonePrefix, req.Sort)
+	if validationErrs.HasErrors() {
+		return validationErrs
+	}
+
+	return s.repo.Stream(ctx, q, fn)
+}
+
+func parseListFilter(validationErrs *ValidationErrors, city, phonePrefix, sort string) domain.ListEmployeesQuery {
+	q := domain.ListEmployeesQuery{
+		Filter: domain.EmployeeFilter{
+			City:        NormalizeString(city),
+			PhonePrefix: NormalizePhonePrefix(phonePrefix),
+		},
+		Sort: domain.SortCreatedAtAsc,
+	}
+
+	if q.Filter.PhonePrefix != "" {
+		if err := ValidatePhonePrefix(q.Filter.PhonePrefix); err != nil {
+			validationErrs.AddError("phonePrefix", err)
+		}
+	}
+
+	switch sort := domain.EmployeeSort(NormalizeString(sort)); sort {
+	case "":
+	case domain.SortCreatedAtAsc, domain.SortCreatedAtDesc:
+		q.Sort = sort
+	default:
+		validationErrs.Add("sort", i18n.OneOf, i18n.Params{"values": "createdAt, -createdAt"})
+	}
+
+	return q
+}
+
+func (s *EmployeeService) SearchEmployees(ctx context.Context, req domain.SearchEmployeesRequest) (*domain.EmployeeSearchResult, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.SearchEmployees")
+	defer span.End()
+
+	validationErrs := &ValidationErrors{}
+
+	query := NormalizeSearchQuery(req.Query)
+	redact.RememberName(ctx, query)
+	if err := ValidateSearchQuery(query); err != nil {
+		validationErrs.AddError("q", err)
+	}
+
+	threshold := s.searchThreshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+		if err := ValidateSearchThreshold(threshold); err != nil {
+			validationErrs.AddError("threshold", err)
+		}
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if err := ValidateListLimit(limit); err != nil {
+		validationErrs.AddError("limit", err)
+	}
+
+	if validationErrs.HasErrors() {
+		return nil, validationErrs
+	}
+
+	hits, err := s.repo.Search(ctx, query, threshold, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.EmployeeSearchResult{Items: hits}, nil
+}
+
 func (s *EmployeeService) HealthCheck(ctx context.Context) error {
+	ctx, span := startSpan(ctx, "EmployeeService.HealthCheck")
+	defer span.End()
+
 	return s.repo.HealthCheck(ctx)
 }
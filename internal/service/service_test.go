@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEmployee_NormalizesAndRejectsDuplicate(t *testing.T) {
+	svc := NewEmployeeService(repository.NewMemoryEmployeeStore())
+	ctx := context.Background()
+
+	emp, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{
+		FullName: "  Иван Иванов ",
+		Phone:    "+77010000001",
+		City:     " Алматы",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Иванов", emp.FullName)
+	assert.Equal(t, "Алматы", emp.City)
+
+	_, err = svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{
+		FullName: "Петр Петров",
+		Phone:    "+77010000001",
+		City:     "Астана",
+	})
+	assert.ErrorIs(t, err, repository.ErrDuplicatePhone)
+
+	_, err = svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "A", Phone: "123", City: ""})
+	var validationErrs *ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	assert.Len(t, validationErrs.Errors, 3)
+}
+
+func TestPatchEmployee_EmptyPatchChecksVersion(t *testing.T) {
+	svc := NewEmployeeService(repository.NewMemoryEmployeeStore())
+	ctx := context.Background()
+
+	emp, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{
+		FullName: "Иван Иванов",
+		Phone:    "+77010000001",
+		City:     "Алматы",
+	})
+	require.NoError(t, err)
+
+	same, err := svc.PatchEmployee(ctx, emp.ID, emp.Version, domain.EmployeePatch{})
+	require.NoError(t, err)
+	assert.Equal(t, emp.Version, same.Version)
+
+	_, err = svc.PatchEmployee(ctx, emp.ID, emp.Version+1, domain.EmployeePatch{})
+	assert.ErrorIs(t, err, repository.ErrVersionMismatch)
+}
+
+func TestListEmployees_CursorPagination(t *testing.T) {
+	svc := NewEmployeeService(repository.NewMemoryEmployeeStore())
+	ctx := context.Background()
+
+	phones := []string{"+77010000001", "+77010000002", "+77010000003", "+77010000004", "+77010000005"}
+	for _, phone := range phones {
+		_, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{
+			FullName: "Сотрудник",
+			Phone:    phone,
+			City:     "Алматы",
+		})
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+	}
+
+	req := domain.ListEmployeesRequest{Sort: "-createdAt", Limit: 2}
+	var seen []string
+	for {
+		list, err := svc.ListEmployees(ctx, req)
+		require.NoError(t, err)
+		for _, emp := range list.Items {
+			seen = append(seen, emp.Phone)
+		}
+		if list.NextCursor == nil {
+			break
+		}
+		req.Cursor = *list.NextCursor
+	}
+
+	assert.Equal(t, []string{"+77010000005", "+77010000004", "+77010000003", "+77010000002", "+77010000001"}, seen)
+
+	_, err := svc.ListEmployees(ctx, domain.ListEmployeesRequest{Sort: "createdAt", Cursor: req.Cursor})
+	var validationErrs *ValidationErrors
+	assert.ErrorAs(t, err, &validationErrs, "курсор привязан к сортировке")
+}
+
+func TestCreateEmployeesBatch_AtomicAbortsOnInvalidItem(t *testing.T) {
+	store := repository.NewMemoryEmployeeStore()
+	svc := NewEmployeeService(store)
+	ctx := context.Background()
+
+	results, err := svc.CreateEmployeesBatch(ctx, domain.BatchCreateEmployeesRequest{
+		Items: []domain.CreateEmployeeRequest{
+			{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"},
+			{FullName: "A", Phone: "+77010000002", City: "Алматы"},
+		},
+	})
+	require.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, repository.ErrBatchAborted)
+	var validationErrs *ValidationErrors
+	assert.ErrorAs(t, results[1].Err, &validationErrs)
+
+	existing, err := store.FindExistingPhones(ctx, []string{"+77010000001"})
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+}
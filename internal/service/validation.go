@@ -2,19 +2,48 @@ package service
 
 import (
 	"errors"
+	"math"
 	"regexp"
 	"strings"
 	"unicode/utf8"
+
+	"employees-api/internal/i18n"
+)
+
+// Ограничения полей. Те же значения описаны в OpenAPI спецификации,
+// transport проверяет их совпадение в тестах.
+const (
+	PhonePattern       = `^\+[1-9]\d{1,14}$`
+	FullNamePattern    = `^[a-zA-Zа-яА-ЯёЁәіңғүұқөһӘІҢҒҮҰҚӨҺ\s\-]+$`
+	PhonePrefixPattern = `^\+\d{1,15}$`
+
+	MinFullNameLength = 2
+	MaxFullNameLength = 200
+	MinCityLength     = 2
+	MaxCityLength     = 120
 )
 
 var (
-	phoneRegex    = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
-	fullNameRegex = regexp.MustCompile(`^[a-zA-Zа-яА-ЯёЁәіңғүұқөһӘІҢҒҮҰҚӨҺ\s\-]+$`)
+	phoneRegex       = regexp.MustCompile(PhonePattern)
+	fullNameRegex    = regexp.MustCompile(FullNamePattern)
+	phonePrefixRegex = regexp.MustCompile(PhonePrefixPattern)
 )
 
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+
+	DefaultSearchThreshold = 0.3
+
+	MaxBatchSize = 1000
+)
+
+// ValidationError хранит код нарушенного правила и его параметры, текст
+// собирается на языке запроса при формировании ответа.
 type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
+	Field  string         `json:"field"`
+	Code   i18n.MessageID `json:"code"`
+	Params i18n.Params    `json:"params,omitempty"`
 }
 
 type ValidationErrors struct {
@@ -25,29 +54,56 @@ func (v *ValidationErrors) Error() string {
 	return "ошибка валидации"
 }
 
-func (v *ValidationErrors) Add(field, message string) {
+func (v *ValidationErrors) Add(field string, code i18n.MessageID, params i18n.Params) {
 	v.Errors = append(v.Errors, ValidationError{
-		Field:   field,
-		Message: message,
+		Field:  field,
+		Code:   code,
+		Params: params,
 	})
 }
 
+// AddError добавляет ошибку валидатора. Ошибки без кода правила попадают в
+// ответ как invalid.
+func (v *ValidationErrors) AddError(field string, err error) {
+	var violation *Violation
+	if errors.As(err, &violation) {
+		v.Add(field, violation.Code, violation.Params)
+		return
+	}
+	v.Add(field, i18n.Invalid, nil)
+}
+
 func (v *ValidationErrors) HasErrors() bool {
 	return len(v.Errors) > 0
 }
 
+// Violation возвращается валидаторами: код правила из каталога сообщений и
+// параметры для подстановки в текст.
+type Violation struct {
+	Code   i18n.MessageID
+	Params i18n.Params
+}
+
+func (v *Violation) Error() string {
+	return i18n.Translate(i18n.Default, v.Code, v.Params)
+}
+
+func violation(code i18n.MessageID, params i18n.Params) error {
+	return &Violation{Code: code, Params: params}
+}
+
 func ValidateFullName(fullName string) error {
 	trimmed := strings.TrimSpace(fullName)
 	length := utf8.RuneCountInString(trimmed)
 
-	if length < 2 {
-		return errors.New("минимум 2 символа")
+	if length < MinFullNameLength {
+		return violation(i18n.MinLength, i18n.Params{"min": MinFullNameLength})
 	}
-	if length > 200 {
-		return errors.New("максимум 200 символов")
+	if length > MaxFullNameLength {
+		return violation(i18n.MaxLength, i18n.Params{"max": MaxFullNameLength})
 	}
 	if !fullNameRegex.MatchString(trimmed) {
-		return errors.New("только буквы, пробелы и дефисы")
+		return violation(i18n.NameCharacters, nil)
 	}
 	return nil
 }
@@ -56,7 +112,7 @@ func ValidatePhone(phone string) error {
 	trimmed := strings.TrimSpace(phone)
 
 	if !phoneRegex.MatchString(trimmed) {
-		return errors.New("формат E.164 (+[1-15 цифр])")
+		return violation(i18n.PhoneFormat, nil)
 	}
 	return nil
 }
@@ -65,15 +121,64 @@ func ValidateCity(city string) error {
 	trimmed := strings.TrimSpace(city)
 	length := utf8.RuneCountInString(trimmed)
 
-	if length < 2 {
-		return errors.New("минимум 2 символа")
+	if length < MinCityLength {
+		return violation(i18n.MinLength, i18n.Params{"min": MinCityLength})
+	}
+	if length > MaxCityLength {
+		return violation(i18n.MaxLength, i18n.Params{"max": MaxCityLength})
 	}
-	if length > 120 {
-		return errors.New("максимум 120 символов")
+	return nil
+}
+
+func ValidatePhonePrefix(prefix string) error {
+	if !phonePrefixRegex.MatchString(prefix) {
+		return violation(i18n.PhonePrefixFormat, nil)
 	}
 	return nil
 }
 
+func ValidateListLimit(limit int) error {
+	if limit < 1 || limit > MaxListLimit {
+		return violation(i18n.Range, i18n.Params{"min": 1, "max": MaxListLimit})
+	}
+	return nil
+}
+
+func ValidateSearchQuery(query string) error {
+	length := utf8.RuneCountInString(query)
+
+	if length < MinFullNameLength {
+		return violation(i18n.MinLength, i18n.Params{"min": MinFullNameLength})
+	}
+	if length > MaxFullNameLength {
+		return violation(i18n.MaxLength, i18n.Params{"max": MaxFullNameLength})
+	}
+	if !fullNameRegex.MatchString(query) {
+		return violation(i18n.NameCharacters, nil)
+	}
+	return nil
+}
+
+func ValidateSearchThreshold(threshold float64) error {
+	// NaN не меньше 0 и не больше 1, поэтому проверяется отдельно.
+	if math.IsNaN(threshold) || math.IsInf(threshold, 0) || threshold < 0 || threshold > 1 {
+		return violation(i18n.Range, i18n.Params{"min": 0, "max": 1})
+	}
+	return nil
+}
+
+func NormalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func NormalizePhonePrefix(prefix string) string {
+	prefix = NormalizeString(prefix)
+	if prefix != "" && !strings.HasPrefix(prefix, "+") {
+		prefix = "+" + prefix
+	}
+	return prefix
+}
+
 func NormalizeString(s string) string {
 	return strings.TrimSpace(s)
 }
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -165,3 +166,148 @@ func TestValidateCity(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePhonePrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		wantError bool
+	}{
+		{
+			name:      "код страны",
+			prefix:    NormalizePhonePrefix("+7"),
+			wantError: false,
+		},
+		{
+			name:      "без плюса",
+			prefix:    NormalizePhonePrefix("7999"),
+			wantError: false,
+		},
+		{
+			name:      "плюс из query string",
+			prefix:    NormalizePhonePrefix(" 7999"),
+			wantError: false,
+		},
+		{
+			name:      "только плюс",
+			prefix:    "+",
+			wantError: true,
+		},
+		{
+			name:      "буквы",
+			prefix:    NormalizePhonePrefix("+7abc"),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePhonePrefix(tt.prefix)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSearchQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantError bool
+	}{
+		{
+			name:      "кириллица",
+			query:     "Иванв",
+			wantError: false,
+		},
+		{
+			name:      "казахские буквы",
+			query:     "Нұрғалиев Әлихан",
+			wantError: false,
+		},
+		{
+			name:      "смешанные алфавиты",
+			query:     "Ivan Иванов",
+			wantError: false,
+		},
+		{
+			name:      "лишние пробелы",
+			query:     NormalizeSearchQuery("  Анна   Мария "),
+			wantError: false,
+		},
+		{
+			name:      "слишком короткий",
+			query:     "И",
+			wantError: true,
+		},
+		{
+			name:      "спецсимволы",
+			query:     "Иван%",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSearchQuery(tt.query)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateSearchThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		wantError bool
+	}{
+		{
+			name:      "ноль",
+			threshold: 0,
+			wantError: false,
+		},
+		{
+			name:      "единица",
+			threshold: 1,
+			wantError: false,
+		},
+		{
+			name:      "отрицательный",
+			threshold: -0.1,
+			wantError: true,
+		},
+		{
+			name:      "больше единицы",
+			threshold: 1.5,
+			wantError: true,
+		},
+		{
+			name:      "NaN",
+			threshold: math.NaN(),
+			wantError: true,
+		},
+		{
+			name:      "бесконечность",
+			threshold: math.Inf(1),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSearchThreshold(tt.threshold)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+)
+
+type APIKeyListResponse struct {
+	Items []domain.APIKey `json:"items"`
+}
+
+func (h *Handler) apiKeyRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/v1/admin/api-keys", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.ListAPIKeys(w, r)
+		case http.MethodPost:
+			h.CreateAPIKey(w, r)
+		default:
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/v1/admin/api-keys/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":rotate") {
+			setRoute(r, "/v1/admin/api-keys/{id}:rotate")
+			if r.Method == http.MethodPost {
+				h.RotateAPIKey(w, r)
+			} else {
+				h.respondMethodNotAllowed(w, r)
+			}
+			return
+		}
+
+		setRoute(r, "/v1/admin/api-keys/{id}")
+		if r.Method == http.MethodDelete {
+			h.RevokeAPIKey(w, r)
+		} else {
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+}
+
+// CreateAPIKey выпускает ключ. Открытое значение есть только в этом ответе.
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_content_type",
+			Message: i18n.T(r.Context(), i18n.MsgContentTypeJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.CreateAPIKeyRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, 64*1024))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_json",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.apiKeys.CreateAPIKey(ctx, req)
+	if err != nil {
+		h.respondAPIKeyError(w, r, err, "ошибка_создания_api_ключа")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, key, http.StatusCreated)
+}
+
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	keys, err := h.apiKeys.ListAPIKeys(ctx)
+	if err != nil {
+		h.respondAPIKeyError(w, r, err, "ошибка_получения_api_ключей")
+		return
+	}
+
+	respondJSON(w, APIKeyListResponse{Items: keys}, http.StatusOK)
+}
+
+// RotateAPIKey выпускает новое значение ключа; прежнее сразу перестает действовать.
+func (h *Handler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parsePathID(w, r, "/v1/admin/api-keys/", ":rotate")
+	if !ok {
+		return
+	}
+
+	key, err := h.apiKeys.RotateAPIKey(ctx, id)
+	if err != nil {
+		h.respondAPIKeyError(w, r, err, "ошибка_ротации_api_ключа")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, key, http.StatusOK)
+}
+
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parsePathID(w, r, "/v1/admin/api-keys/", "")
+	if !ok {
+		return
+	}
+
+	if err := h.apiKeys.RevokeAPIKey(ctx, id); err != nil {
+		h.respondAPIKeyError(w, r, err, "ошибка_отзыва_api_ключа")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondAPIKeyError(w http.ResponseWriter, r *http.Request, err error, logMsg string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "not_found",
+			Message: i18n.T(r.Context(), i18n.MsgAPIKeyNotFound, nil),
+		}, http.StatusNotFound)
+		return
+	}
+	h.respondServiceError(w, r, err, logMsg)
+}
@@ -0,0 +1,93 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employees-api/internal/auth/authtest"
+	"employees-api/internal/authz"
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	server := authtest.NewServer(t)
+	policy, err := authz.ParsePolicy([]byte(`{"roles": {
+		"admin": [{"actions": ["apikeys.*"]}],
+		"hr_admin": [{"actions": ["employees.*"]}]
+	}}`))
+	require.NoError(t, err)
+
+	svc := authz.NewEmployeeService(service.NewEmployeeService(repository.NewMemoryEmployeeStore()), authz.Static(policy))
+	keys := authz.NewAPIKeyService(service.NewAPIKeyService(repository.NewMemoryAPIKeyStore()), authz.Static(policy))
+	routes := NewHandler(svc, NewLogger(), WithAuthentication(server.Authenticator()), WithAPIKeys(keys)).Routes()
+
+	bearer := func(role string) string {
+		return "Bearer " + server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"role": role}))
+	}
+	do := func(method, path, body string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/v1/admin/api-keys", `{"name": "payroll", "scopes": ["employees.read", "employees.list"]}`, "Authorization", bearer("hr_admin"))
+	require.Equal(t, http.StatusForbidden, rec.Code, "управлять ключами может только admin")
+
+	rec = do(http.MethodPost, "/v1/admin/api-keys", `{"name": "payroll", "scopes": ["employees.read", "employees.list"]}`, "Authorization", bearer("admin"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	var issued domain.IssuedAPIKey
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&issued))
+	require.NotEmpty(t, issued.Key)
+
+	rec = do(http.MethodGet, "/v1/admin/api-keys", "", "Authorization", bearer("admin"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), issued.Key, "открытое значение показывается только при создании")
+	assert.Contains(t, rec.Body.String(), issued.Prefix)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/employees", "", "X-API-Key", issued.Key).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/employees", "", "Authorization", "ApiKey "+issued.Key).Code)
+
+	rec = do(http.MethodPost, "/v1/employees", `{"fullName": "Иван Иванов", "phone": "+77010000001", "city": "Алматы"}`, "X-API-Key", issued.Key)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "действия вне scopes запрещены")
+
+	rec = do(http.MethodGet, "/v1/admin/api-keys", "", "X-API-Key", issued.Key)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "ключ не управляет ключами")
+
+	rec = do(http.MethodPost, "/v1/admin/api-keys/"+issued.ID.String()+":rotate", "", "Authorization", bearer("admin"))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var rotated domain.IssuedAPIKey
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&rotated))
+
+	rec = do(http.MethodGet, "/v1/employees", "", "X-API-Key", issued.Key)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `ApiKey error="invalid_key"`, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/v1/employees", "", "X-API-Key", rotated.Key).Code)
+
+	rec = do(http.MethodDelete, "/v1/admin/api-keys/"+issued.ID.String(), "", "Authorization", bearer("admin"))
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/v1/employees", "", "X-API-Key", rotated.Key).Code)
+
+	rec = do(http.MethodPost, "/v1/admin/api-keys/"+issued.ID.String()+":rotate", "", "Authorization", bearer("admin"))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(http.MethodGet, "/v1/employees", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, []string{"Bearer", "ApiKey"}, rec.Header().Values("WWW-Authenticate"))
+}
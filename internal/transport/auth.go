@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/service"
+)
+
+// authMiddleware пропускает только запросы с валидным bearer токеном или API
+// ключом и кладет личность клиента в контекст (auth.IdentityFromContext).
+func (h *Handler) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.authExempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if key, ok := apiKeyFromRequest(r); ok && h.apiKeys != nil {
+			h.authenticateAPIKey(w, r, next, key)
+			return
+		}
+
+		token, ok := auth.BearerToken(r.Header.Get("Authorization"))
+		if !ok || h.auth == nil {
+			h.setChallenges(w)
+			h.respondError(w, r, ErrorResponse{
+				Code:    "unauthorized",
+				Message: i18n.T(r.Context(), i18n.MsgAuthenticationRequired, nil),
+			}, http.StatusUnauthorized)
+			return
+		}
+
+		identity, err := h.auth.Authenticate(r.Context(), token)
+		if err != nil {
+			h.logger.InfoContext(r.Context(), "ошибка_аутентификации", slog.Any(LogKeyError, err))
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			h.respondError(w, r, ErrorResponse{
+				Code:    "unauthorized",
+				Message: i18n.T(r.Context(), i18n.MsgInvalidToken, nil),
+			}, http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	})
+}
+
+func (h *Handler) authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, plaintext string) {
+	key, err := h.apiKeys.AuthenticateAPIKey(r.Context(), plaintext)
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidAPIKey) {
+			h.logger.ErrorContext(r.Context(), "ошибка_проверки_api_ключа",
+				slog.String(LogKeyErrorType, "внутренняя"),
+				slog.Any(LogKeyError, err),
+			)
+			h.respondError(w, r, ErrorResponse{
+				Code:    "internal_error",
+				Message: i18n.T(r.Context(), i18n.MsgInternalError, nil),
+			}, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `ApiKey error="invalid_key"`)
+		h.respondError(w, r, ErrorResponse{
+			Code:    "unauthorized",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidAPIKey, nil),
+		}, http.StatusUnauthorized)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), APIKeyIdentity(key))))
+}
+
+// setChallenges перечисляет схемы аутентификации, которые принимает сервис.
+func (h *Handler) setChallenges(w http.ResponseWriter) {
+	if h.auth != nil {
+		w.Header().Add("WWW-Authenticate", `Bearer`)
+	}
+	if h.apiKeys != nil {
+		w.Header().Add("WWW-Authenticate", `ApiKey`)
+	}
+}
+
+func apiKeyFromRequest(r *http.Request) (string, bool) {
+	if key := strings.TrimSpace(r.Header.Get("X-API-Key")); key != "" {
+		return key, true
+	}
+	scheme, key, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "ApiKey") {
+		return "", false
+	}
+	key = strings.TrimSpace(key)
+	return key, key != ""
+}
+
+// APIKeyIdentity представляет ключ как клиента с subject "apikey:<id>".
+// Scopes не nil, поэтому authz проверяет действия ключа, а не роли.
+func APIKeyIdentity(key *domain.APIKey) *auth.Identity {
+	subject := "apikey:" + key.ID.String()
+	return &auth.Identity{
+		Subject: subject,
+		Claims:  map[string]interface{}{"sub": subject, "name": key.Name},
+		Scopes:  append([]string{}, key.Scopes...),
+	}
+}
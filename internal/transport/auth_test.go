@@ -0,0 +1,133 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/auth/authtest"
+	"employees-api/internal/authz"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	server := authtest.NewServer(t)
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger(), WithAuthentication(server.Authenticator(), "/v1/healthz")).Routes()
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{
+			name:          "без токена",
+			path:          "/v1/employees",
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: "Bearer",
+		},
+		{
+			name:          "другая схема",
+			path:          "/v1/employees",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: "Bearer",
+		},
+		{
+			name:          "просроченный токен",
+			path:          "/v1/employees",
+			authorization: "Bearer " + server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "валидный токен",
+			path:          "/v1/employees",
+			authorization: "Bearer " + server.SignES256(t, authtest.Claims("user-1", nil)),
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:       "healthz без токена",
+			path:       "/v1/healthz",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantChallenge, rec.Header().Get("WWW-Authenticate"))
+			if tt.wantStatus == http.StatusUnauthorized {
+				var errResp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+				assert.Equal(t, "unauthorized", errResp.Code)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_PutsIdentityIntoContext(t *testing.T) {
+	server := authtest.NewServer(t)
+	h := NewHandler(nil, NewLogger(), WithAuthentication(server.Authenticator()))
+
+	var identity *auth.Identity
+	handler := h.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ = auth.IdentityFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/employees", nil)
+	req.Header.Set("Authorization", "Bearer "+server.SignRS256(t, authtest.Claims("user-7", jwt.MapClaims{"role": "hr_admin"})))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, identity)
+	assert.Equal(t, "user-7", identity.Subject)
+	assert.Equal(t, "hr_admin", identity.StringClaim("role"))
+}
+
+func TestAuthorization_Forbidden(t *testing.T) {
+	server := authtest.NewServer(t)
+	policy, err := authz.ParsePolicy([]byte(`{"roles": {
+		"hr_admin": [{"actions": ["employees.*"]}],
+		"manager": [{"actions": ["employees.read", "employees.list"]}]
+	}}`))
+	require.NoError(t, err)
+
+	svc := authz.NewEmployeeService(service.NewEmployeeService(repository.NewMemoryEmployeeStore()), authz.Static(policy))
+	routes := NewHandler(svc, NewLogger(), WithAuthentication(server.Authenticator())).Routes()
+
+	create := func(role string) *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"fullName": "Иван Иванов", "phone": "+77010000001", "city": "Алматы"}`)
+		req := httptest.NewRequest(http.MethodPost, "/v1/employees", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"role": role})))
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := create("manager")
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	var errResp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+	assert.Equal(t, "forbidden", errResp.Code)
+
+	assert.Equal(t, http.StatusCreated, create("hr_admin").Code)
+}
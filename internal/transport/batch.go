@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+)
+
+type BatchItemResult struct {
+	Index    int              `json:"index"`
+	Status   int              `json:"status"`
+	Employee *domain.Employee `json:"employee,omitempty"`
+	Error    *ErrorResponse   `json:"error,omitempty"`
+}
+
+type BatchCreateResponse struct {
+	Mode    domain.BatchMode  `json:"mode"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Items   []BatchItemResult `json:"items"`
+}
+
+func (h *Handler) CreateEmployeesBatch(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_content_type",
+			Message: i18n.T(r.Context(), i18n.MsgContentTypeJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.BatchCreateEmployeesRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, 5*1024*1024))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_json",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.service.CreateEmployeesBatch(ctx, req)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_пакетного_создания_сотрудников")
+		return
+	}
+
+	resp := BatchCreateResponse{
+		Mode:  req.Mode,
+		Items: make([]BatchItemResult, len(results)),
+	}
+	if resp.Mode == "" {
+		resp.Mode = domain.BatchModeAtomic
+	}
+
+	for i, result := range results {
+		item := BatchItemResult{Index: i}
+		if result.Err == nil {
+			item.Status = http.StatusCreated
+			item.Employee = result.Employee
+			resp.Created++
+		} else {
+			errResp, status := h.batchItemError(r, result.Err)
+			item.Status = status
+			item.Error = &errResp
+			resp.Failed++
+		}
+		resp.Items[i] = item
+	}
+
+	respondJSON(w, resp, http.StatusMultiStatus)
+}
+
+func (h *Handler) batchItemError(r *http.Request, err error) (ErrorResponse, int) {
+	var validationErr *service.ValidationErrors
+	switch {
+	case errors.As(err, &validationErr):
+		h.observeValidationErrors(validationErr)
+		return validationErrorResponse(r, validationErr), http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrDuplicatePhone):
+		return ErrorResponse{
+			Code:    "duplicate_phone",
+			Message: i18n.T(r.Context(), i18n.MsgDuplicatePhone, nil),
+		}, http.StatusConflict
+	default:
+		return ErrorResponse{
+			Code:    "batch_aborted",
+			Message: i18n.T(r.Context(), i18n.MsgBatchAborted, nil),
+		}, http.StatusFailedDependency
+	}
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+
+	"github.com/google/uuid"
+)
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func setETag(w http.ResponseWriter, emp *domain.Employee) {
+	w.Header().Set("ETag", formatETag(emp.Version))
+}
+
+// parseETagList разбирает значение If-Match / If-None-Match. Слабые ETag
+// пропускаются: If-Match требует строгого сравнения, а сервер выдает только строгие.
+func parseETagList(header string) (versions []int64, wildcard bool) {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(part)
+		if tag == "*" {
+			return nil, true
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version <= 0 {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, false
+}
+
+func containsVersion(versions []int64, version int64) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// requireIfMatch возвращает версию, которую ожидает клиент, или 0 для If-Match: *.
+// Без заголовка отвечает 428, при заведомом несовпадении 412.
+func (h *Handler) requireIfMatch(ctx context.Context, w http.ResponseWriter, r *http.Request, id uuid.UUID) (int64, bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "precondition_required",
+			Message: i18n.T(r.Context(), i18n.MsgPreconditionRequired, nil),
+		}, http.StatusPreconditionRequired)
+		return 0, false
+	}
+
+	versions, wildcard := parseETagList(header)
+	switch {
+	case wildcard:
+		return 0, true
+	case len(versions) == 1:
+		return versions[0], true
+	case len(versions) == 0:
+		h.respondPreconditionFailed(w, r)
+		return 0, false
+	}
+
+	emp, err := h.service.GetEmployeeByID(ctx, id)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_получения_сотрудника")
+		return 0, false
+	}
+	if !containsVersion(versions, emp.Version) {
+		h.respondPreconditionFailed(w, r)
+		return 0, false
+	}
+	return emp.Version, true
+}
+
+func notModified(r *http.Request, emp *domain.Employee) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	versions, wildcard := parseETagList(strings.ReplaceAll(header, `W/"`, `"`))
+	return wildcard || containsVersion(versions, emp.Version)
+}
+
+func (h *Handler) respondPreconditionFailed(w http.ResponseWriter, r *http.Request) {
+	h.respondError(w, r, ErrorResponse{
+		Code:    "precondition_failed",
+		Message: i18n.T(r.Context(), i18n.MsgPreconditionFailed, nil),
+	}, http.StatusPreconditionFailed)
+}
@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseETagList(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		wantVersions []int64
+		wantWildcard bool
+	}{
+		{
+			name:         "одна версия",
+			header:       `"3"`,
+			wantVersions: []int64{3},
+		},
+		{
+			name:         "список версий",
+			header:       `"3", "5"`,
+			wantVersions: []int64{3, 5},
+		},
+		{
+			name:         "любая версия",
+			header:       `*`,
+			wantWildcard: true,
+		},
+		{
+			name:   "слабый ETag",
+			header: `W/"3"`,
+		},
+		{
+			name:   "без кавычек",
+			header: `3`,
+		},
+		{
+			name:   "не число",
+			header: `"abc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, wildcard := parseETagList(tt.header)
+			assert.Equal(t, tt.wantVersions, versions)
+			assert.Equal(t, tt.wantWildcard, wildcard)
+		})
+	}
+}
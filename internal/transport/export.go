@@ -0,0 +1,244 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/service"
+	"employees-api/internal/xlsx"
+)
+
+const exportFlushEvery = 500
+
+var exportColumns = []string{"id", "fullName", "phone", "city", "createdAt", "updatedAt"}
+
+// exportEncoder пишет сотрудников в тело ответа в одном из форматов выгрузки.
+type exportEncoder interface {
+	Write(emp *domain.Employee) error
+	Flush() error
+	Close() error
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	newEncoder  func(w http.ResponseWriter) (exportEncoder, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv": {
+		contentType: "text/csv; charset=utf-8",
+		extension:   "csv",
+		newEncoder:  newCSVExportEncoder,
+	},
+	"ndjson": {
+		contentType: "application/x-ndjson",
+		extension:   "ndjson",
+		newEncoder:  newNDJSONExportEncoder,
+	},
+	"xlsx": {
+		contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		extension:   "xlsx",
+		newEncoder:  newXLSXExportEncoder,
+	},
+}
+
+// ExportEmployees отдает всех сотрудников, подходящих под фильтры списка, потоком:
+// строки пишутся в ответ по мере чтения из курсора БД.
+func (h *Handler) ExportEmployees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.exportTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.exportTimeout)
+		defer cancel()
+	}
+
+	query := r.URL.Query()
+
+	formatName := query.Get("format")
+	if formatName == "" {
+		formatName = "csv"
+	}
+	format, ok := exportFormats[formatName]
+	if !ok {
+		validationErrs := &service.ValidationErrors{}
+		validationErrs.Add("format", i18n.OneOf, i18n.Params{"values": "csv, ndjson, xlsx"})
+		h.respondValidationError(w, r, validationErrs)
+		return
+	}
+
+	req := domain.ExportEmployeesRequest{
+		City:        query.Get("city"),
+		PhonePrefix: query.Get("phonePrefix"),
+		Sort:        query.Get("sort"),
+	}
+
+	// Серверный WriteTimeout рассчитан на обычные запросы и оборвал бы долгую выгрузку.
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	var enc exportEncoder
+	rows := 0
+
+	// Заголовки отправляются только перед первой строкой, чтобы ошибки валидации
+	// и БД до начала выгрузки вернулись обычным JSON-ответом.
+	start := func() error {
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="employees-`+
+			time.Now().UTC().Format("20060102-150405")+"."+format.extension+`"`)
+		w.WriteHeader(http.StatusOK)
+
+		var err error
+		enc, err = format.newEncoder(w)
+		return err
+	}
+
+	err := h.service.ExportEmployees(ctx, req, func(emp *domain.Employee) error {
+		if enc == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := enc.Write(emp); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			rc.Flush()
+		}
+		return nil
+	})
+
+	if err != nil && enc == nil {
+		h.respondServiceError(w, r, err, "ошибка_выгрузки_сотрудников")
+		return
+	}
+
+	if err == nil && enc == nil {
+		err = start()
+	}
+	if err == nil {
+		err = enc.Close()
+	}
+
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "ошибка_выгрузки_сотрудников",
+			slog.Int(LogKeyExported, rows),
+			slog.Any(LogKeyError, err),
+		)
+		// Статус уже отправлен: обрываем соединение, чтобы клиент не принял
+		// усеченный файл за полный.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func exportRecord(emp *domain.Employee) []string {
+	return []string{
+		emp.ID.String(),
+		emp.FullName,
+		emp.Phone,
+		emp.City,
+		emp.CreatedAt.UTC().Format(time.RFC3339Nano),
+		emp.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+type csvExportEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVExportEncoder(w http.ResponseWriter) (exportEncoder, error) {
+	enc := &csvExportEncoder{w: csv.NewWriter(w)}
+	if err := enc.w.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	return enc, nil
+}
+
+func (e *csvExportEncoder) Write(emp *domain.Employee) error {
+	record := exportRecord(emp)
+	// Телефон проверен по E.164, ID и даты формирует сервис; свободный текст
+	// - только ФИО и город.
+	record[1] = csvSafe(record[1])
+	record[3] = csvSafe(record[3])
+	return e.w.Write(record)
+}
+
+// csvSafe экранирует значение, которое Excel выполнил бы как формулу,
+// апострофом в начале.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *csvExportEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExportEncoder) Close() error {
+	return e.Flush()
+}
+
+type ndjsonExportEncoder struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONExportEncoder(w http.ResponseWriter) (exportEncoder, error) {
+	buf := bufio.NewWriter(w)
+	return &ndjsonExportEncoder{buf: buf, enc: json.NewEncoder(buf)}, nil
+}
+
+func (e *ndjsonExportEncoder) Write(emp *domain.Employee) error {
+	return e.enc.Encode(emp)
+}
+
+func (e *ndjsonExportEncoder) Flush() error {
+	return e.buf.Flush()
+}
+
+func (e *ndjsonExportEncoder) Close() error {
+	return e.buf.Flush()
+}
+
+type xlsxExportEncoder struct {
+	w *xlsx.Writer
+}
+
+func newXLSXExportEncoder(w http.ResponseWriter) (exportEncoder, error) {
+	xw, err := xlsx.NewWriter(w, "employees")
+	if err != nil {
+		return nil, err
+	}
+	if err := xw.WriteRow(exportColumns); err != nil {
+		return nil, err
+	}
+	return &xlsxExportEncoder{w: xw}, nil
+}
+
+func (e *xlsxExportEncoder) Write(emp *domain.Employee) error {
+	return e.w.WriteRow(exportRecord(emp))
+}
+
+func (e *xlsxExportEncoder) Flush() error {
+	return e.w.Flush()
+}
+
+func (e *xlsxExportEncoder) Close() error {
+	return e.w.Close()
+}
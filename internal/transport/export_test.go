@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Алматы", "Алматы"},
+		{"", ""},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"Усть-Каменогорск", "Усть-Каменогорск"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, csvSafe(tt.value), tt.value)
+	}
+}
+
+func TestExportCSV_EscapesFormulas(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger()).Routes()
+
+	emp, err := svc.CreateEmployee(context.Background(), domain.CreateEmployeeRequest{
+		FullName: "Иван Иванов",
+		Phone:    "+77010000001",
+		City:     "=HYPERLINK(\"http://evil\")",
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/employees:export?format=csv", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{emp.ID.String(), "Иван Иванов", "+77010000001", "'=HYPERLINK(\"http://evil\")"}, records[1][:4],
+		"город экранирован, телефон E.164 - нет")
+}
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"employees-api/internal/authz"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// WithGraphQL включает /v1/graphql. Запросы дороже maxCost (см. queryCost)
+// отклоняются до выполнения, чтобы один запрос не занял пул соединений с БД.
+func WithGraphQL(maxCost int) HandlerOption {
+	return func(h *Handler) {
+		h.graphQLMaxCost = maxCost
+	}
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLError - ошибка GraphQL с кодом в extensions.code, тем же, что code
+// в ErrorResponse REST API.
+type graphQLError struct {
+	code    string
+	message string
+	params  map[string]interface{}
+	errors  []FieldError
+}
+
+func (e *graphQLError) Error() string {
+	return e.message
+}
+
+func (e *graphQLError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	for k, v := range e.params {
+		extensions[k] = v
+	}
+	if len(e.errors) > 0 {
+		extensions["errors"] = e.errors
+	}
+	return extensions
+}
+
+func (h *Handler) graphQLRoutes(mux *http.ServeMux) {
+	schema, err := h.graphQLSchema()
+	if err != nil {
+		// Схема статическая, ошибка в ней - ошибка программы.
+		panic(err)
+	}
+
+	mux.HandleFunc("/v1/graphql", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			h.GraphQL(w, r, schema)
+		} else {
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+}
+
+// GraphQL выполняет запрос {query, operationName, variables}. Ошибки разбора,
+// валидации и выполнения возвращаются со статусом 200 в поле errors ответа.
+func (h *Handler) GraphQL(w http.ResponseWriter, r *http.Request, schema graphql.Schema) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_content_type",
+			Message: i18n.T(r.Context(), i18n.MsgContentTypeJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	var req graphQLRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1024*1024)).Decode(&req); err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_json",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "graphql_query_required",
+			Message: i18n.T(r.Context(), i18n.MsgGraphQLQueryRequired, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		respondJSON(w, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, http.StatusOK)
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&schema, doc, nil); !validation.IsValid {
+		respondJSON(w, &graphql.Result{Errors: validation.Errors}, http.StatusOK)
+		return
+	}
+
+	if queryCost(doc, req.OperationName, req.Variables, h.graphQLMaxCost) > h.graphQLMaxCost {
+		params := i18n.Params{"max": h.graphQLMaxCost}
+		err := &graphQLError{
+			code:    "query_too_complex",
+			message: i18n.T(r.Context(), i18n.MsgQueryTooComplex, params),
+			params:  params,
+		}
+		respondJSON(w, &graphql.Result{Errors: gqlerrors.FormatErrors(gqlerrors.NewError(err.message, nil, "", nil, nil, err))}, http.StatusOK)
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withEmployeeLoader(ctx, newEmployeeLoader(h.service)),
+	})
+	respondJSON(w, result, http.StatusOK)
+}
+
+// graphQLServiceError переводит ошибку сервиса в ошибку GraphQL по тем же
+// правилам, что и respondServiceError.
+func (h *Handler) graphQLServiceError(ctx context.Context, err error, logMsg string) error {
+	var validationErr *service.ValidationErrors
+	switch {
+	case errors.As(err, &validationErr):
+		h.observeValidationErrors(validationErr)
+		return &graphQLError{
+			code:    "validation_error",
+			message: i18n.T(ctx, i18n.MsgValidationError, nil),
+			errors:  fieldErrors(ctx, validationErr),
+		}
+	case errors.Is(err, repository.ErrDuplicatePhone):
+		return &graphQLError{code: "duplicate_phone", message: i18n.T(ctx, i18n.MsgDuplicatePhone, nil)}
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return &graphQLError{code: "precondition_failed", message: i18n.T(ctx, i18n.MsgPreconditionFailed, nil)}
+	case errors.Is(err, authz.ErrForbidden):
+		return &graphQLError{code: "forbidden", message: i18n.T(ctx, i18n.MsgForbidden, nil)}
+	case errors.Is(err, repository.ErrNotFound):
+		return &graphQLError{code: "not_found", message: i18n.T(ctx, i18n.MsgEmployeeNotFound, nil)}
+	default:
+		h.logger.ErrorContext(ctx, logMsg,
+			slog.String(LogKeyErrorType, "внутренняя"),
+			slog.Any(LogKeyError, err),
+		)
+		return &graphQLError{code: "internal_error", message: i18n.T(ctx, i18n.MsgInternalError, nil)}
+	}
+}
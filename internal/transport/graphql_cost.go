@@ -0,0 +1,161 @@
+package transport
+
+import (
+	"math"
+	"strconv"
+
+	"employees-api/internal/service"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DefaultGraphQLMaxCost - предельная стоимость запроса GraphQL по умолчанию:
+// хватает на страницу из MaxListLimit сотрудников со всеми полями, но не на
+// несколько таких страниц в одном запросе.
+const DefaultGraphQLMaxCost = 1000
+
+// graphQLListArgs - поля, возвращающие страницу сотрудников, и аргумент с
+// размером страницы.
+var graphQLListArgs = map[string]string{
+	"employees": "limit",
+}
+
+// queryCost оценивает стоимость операции до ее выполнения. Каждое поле стоит
+// 1, а выборка внутри списка умножается на размер страницы: limit из
+// аргумента или переменной, DefaultListLimit без него. Так стоимость растет
+// вместе с числом строк и запросов к БД, которые потребует операция.
+//
+// Точная стоимость сверх maxCost не нужна: подсчет насыщается на maxCost+1 и
+// прекращается, как только предел превышен. Стоимость каждого фрагмента
+// считается один раз, иначе фрагменты, дважды включающие друг друга по
+// цепочке, проходились бы экспоненциальное число раз.
+func queryCost(doc *ast.Document, operationName string, variables map[string]interface{}, maxCost int) int {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		return 0
+	}
+
+	if maxCost >= math.MaxInt {
+		maxCost = math.MaxInt - 1
+	}
+	c := costCounter{
+		fragments:     fragments,
+		variables:     variables,
+		max:           maxCost,
+		fragmentCosts: make(map[string]int),
+		visiting:      make(map[string]bool),
+	}
+	return c.selectionSet(operation.SelectionSet)
+}
+
+type costCounter struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	max       int
+
+	fragmentCosts map[string]int
+	// visiting защищает от циклов во фрагментах, если запрос не прошел бы
+	// валидацию.
+	visiting map[string]bool
+}
+
+func (c costCounter) selectionSet(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+
+	cost := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			children := c.selectionSet(selection.SelectionSet)
+			if arg, ok := graphQLListArgs[selection.Name.Value]; ok {
+				children = c.mul(children, c.pageSize(selection, arg))
+			}
+			cost = c.add(cost, c.add(1, children))
+		case *ast.InlineFragment:
+			cost = c.add(cost, c.selectionSet(selection.SelectionSet))
+		case *ast.FragmentSpread:
+			cost = c.add(cost, c.fragment(selection.Name.Value))
+		}
+		if cost > c.max {
+			return cost
+		}
+	}
+	return cost
+}
+
+func (c costCounter) fragment(name string) int {
+	if cost, ok := c.fragmentCosts[name]; ok {
+		return cost
+	}
+	fragment, ok := c.fragments[name]
+	if !ok || c.visiting[name] {
+		return 0
+	}
+
+	c.visiting[name] = true
+	cost := c.selectionSet(fragment.SelectionSet)
+	delete(c.visiting, name)
+	c.fragmentCosts[name] = cost
+	return cost
+}
+
+// add и mul насыщаются на max+1, поэтому не переполняются при любом limit и
+// глубине вложенности.
+func (c costCounter) add(a, b int) int {
+	if a > c.max-b {
+		return c.max + 1
+	}
+	return a + b
+}
+
+func (c costCounter) mul(a, b int) int {
+	if b != 0 && a > c.max/b {
+		return c.max + 1
+	}
+	return a * b
+}
+
+// pageSize возвращает размер страницы, как его определит сервис: 0 означает
+// DefaultListLimit, а больше MaxListLimit сервис не вернет.
+func (c costCounter) pageSize(field *ast.Field, argName string) int {
+	size := service.DefaultListLimit
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != argName {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil {
+				size = n
+			}
+		case *ast.Variable:
+			switch n := c.variables[value.Name.Value].(type) {
+			case float64:
+				size = int(n)
+			case int:
+				size = n
+			}
+		}
+	}
+
+	if size <= 0 {
+		return service.DefaultListLimit
+	}
+	if size > service.MaxListLimit {
+		return service.MaxListLimit
+	}
+	return size
+}
@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"context"
+	"sync"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type employeeLoaderKey struct{}
+
+// employeeLoader объединяет загрузки сотрудников по ID в пакеты. Резолверы
+// одного уровня запроса вызывают Load и возвращают исполнителю GraphQL
+// отложенные значения; первое обращение к ним загружает весь накопленный
+// пакет одним вызовом GetEmployeesByIDs (WHERE id = ANY($1)). Загрузчик живет
+// один запрос: права клиента проверяются при каждой загрузке.
+type employeeLoader struct {
+	service service.Employees
+
+	mu    sync.Mutex
+	batch *employeeBatch
+}
+
+type employeeBatch struct {
+	ids  []uuid.UUID
+	once sync.Once
+
+	employees map[uuid.UUID]*domain.Employee
+	err       error
+}
+
+func newEmployeeLoader(svc service.Employees) *employeeLoader {
+	return &employeeLoader{service: svc}
+}
+
+func withEmployeeLoader(ctx context.Context, loader *employeeLoader) context.Context {
+	return context.WithValue(ctx, employeeLoaderKey{}, loader)
+}
+
+func employeeLoaderFromContext(ctx context.Context) *employeeLoader {
+	loader, _ := ctx.Value(employeeLoaderKey{}).(*employeeLoader)
+	return loader
+}
+
+// Load ставит id в текущий пакет. Возвращенная функция дожидается загрузки
+// пакета; для ненайденного сотрудника возвращает nil без ошибки.
+func (l *employeeLoader) Load(ctx context.Context, id uuid.UUID) func() (*domain.Employee, error) {
+	l.mu.Lock()
+	if l.batch == nil {
+		l.batch = &employeeBatch{}
+	}
+	batch := l.batch
+	batch.ids = append(batch.ids, id)
+	l.mu.Unlock()
+
+	return func() (*domain.Employee, error) {
+		batch.once.Do(func() {
+			// Следующие вызовы Load собирают новый пакет.
+			l.mu.Lock()
+			if l.batch == batch {
+				l.batch = nil
+			}
+			ids := batch.ids
+			l.mu.Unlock()
+
+			batch.load(ctx, l.service, ids)
+		})
+		return batch.employees[id], batch.err
+	}
+}
+
+func (b *employeeBatch) load(ctx context.Context, svc service.Employees, ids []uuid.UUID) {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	employees, err := svc.GetEmployeesByIDs(ctx, unique)
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	b.employees = make(map[uuid.UUID]*domain.Employee, len(employees))
+	for i := range employees {
+		b.employees[employees[i].ID] = &employees[i]
+	}
+}
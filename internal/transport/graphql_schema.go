@@ -0,0 +1,228 @@
+package transport
+
+import (
+	"context"
+	"errors"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+// graphQLSchema описывает схему /v1/graphql. Резолверы вызывают тот же
+// service.Employees, что и REST обработчики.
+func (h *Handler) graphQLSchema() (graphql.Schema, error) {
+	employeeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Employee",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*domain.Employee).ID.String(), nil
+				},
+			},
+			"fullName":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"phone":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"city":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"version": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Версия для оптимистичной блокировки, передается в updateEmployee.",
+			},
+		},
+	})
+
+	employeeListType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EmployeeList",
+		Fields: graphql.Fields{
+			"items": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(employeeType)))},
+			"nextCursor": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Курсор следующей страницы для аргумента cursor; null на последней странице.",
+			},
+		},
+	})
+
+	fieldErrorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "FieldError",
+		Description: "Нарушенное правило валидации поля; code совпадает с кодом правила в REST API.",
+		Fields: graphql.Fields{
+			"field":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"code":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"message": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	payloadType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "EmployeePayload",
+		Description: "Результат изменения: employee при успехе или errors при ошибках валидации.",
+		Fields: graphql.Fields{
+			"employee": &graphql.Field{Type: employeeType},
+			"errors":   &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(fieldErrorType)))},
+		},
+	})
+
+	employeeInput := func(name string) *graphql.InputObject {
+		return graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: name,
+			Fields: graphql.InputObjectConfigFieldMap{
+				"fullName": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"phone":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"city":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+		})
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"employee": &graphql.Field{
+				Type:        employeeType,
+				Description: "Сотрудник по ID; null, если не найден.",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: h.resolveEmployee,
+			},
+			"employees": &graphql.Field{
+				Type:        graphql.NewNonNull(employeeListType),
+				Description: "Страница сотрудников с фильтрами, как GET /v1/employees.",
+				Args: graphql.FieldConfigArgument{
+					"city":        &graphql.ArgumentConfig{Type: graphql.String},
+					"phonePrefix": &graphql.ArgumentConfig{Type: graphql.String},
+					"sort":        &graphql.ArgumentConfig{Type: graphql.String, Description: "createdAt или -createdAt"},
+					"cursor":      &graphql.ArgumentConfig{Type: graphql.String},
+					"limit":       &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: h.resolveEmployees,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createEmployee": &graphql.Field{
+				Type: graphql.NewNonNull(payloadType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(employeeInput("CreateEmployeeInput"))},
+				},
+				Resolve: h.resolveCreateEmployee,
+			},
+			"updateEmployee": &graphql.Field{
+				Type: graphql.NewNonNull(payloadType),
+				Args: graphql.FieldConfigArgument{
+					"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"version": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int), Description: "Ожидаемая версия сотрудника."},
+					"input":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(employeeInput("UpdateEmployeeInput"))},
+				},
+				Resolve: h.resolveUpdateEmployee,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// resolveEmployee не ходит в БД сам: ID попадает в пакет employeeLoader, и
+// все employee(id) одного уровня запроса загружаются одним запросом.
+func (h *Handler) resolveEmployee(p graphql.ResolveParams) (interface{}, error) {
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return nil, &graphQLError{code: "invalid_id", message: i18n.T(p.Context, i18n.MsgInvalidID, nil)}
+	}
+
+	load := employeeLoaderFromContext(p.Context).Load(p.Context, id)
+	return func() (interface{}, error) {
+		emp, err := load()
+		if err != nil {
+			return nil, h.graphQLServiceError(p.Context, err, "ошибка_получения_сотрудника")
+		}
+		if emp == nil {
+			return nil, nil
+		}
+		return emp, nil
+	}, nil
+}
+
+func (h *Handler) resolveEmployees(p graphql.ResolveParams) (interface{}, error) {
+	req := domain.ListEmployeesRequest{}
+	req.City, _ = p.Args["city"].(string)
+	req.PhonePrefix, _ = p.Args["phonePrefix"].(string)
+	req.Sort, _ = p.Args["sort"].(string)
+	req.Cursor, _ = p.Args["cursor"].(string)
+	req.Limit, _ = p.Args["limit"].(int)
+
+	list, err := h.service.ListEmployees(p.Context, req)
+	if err != nil {
+		return nil, h.graphQLServiceError(p.Context, err, "ошибка_получения_списка_сотрудников")
+	}
+
+	items := make([]*domain.Employee, 0, len(list.Items))
+	for i := range list.Items {
+		items = append(items, &list.Items[i])
+	}
+	return map[string]interface{}{
+		"items":      items,
+		"nextCursor": list.NextCursor,
+	}, nil
+}
+
+func (h *Handler) resolveCreateEmployee(p graphql.ResolveParams) (interface{}, error) {
+	input := p.Args["input"].(map[string]interface{})
+	emp, err := h.service.CreateEmployee(p.Context, domain.CreateEmployeeRequest{
+		FullName: input["fullName"].(string),
+		Phone:    input["phone"].(string),
+		City:     input["city"].(string),
+	})
+	return h.employeePayload(p.Context, emp, err, "ошибка_создания_сотрудника")
+}
+
+func (h *Handler) resolveUpdateEmployee(p graphql.ResolveParams) (interface{}, error) {
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return nil, &graphQLError{code: "invalid_id", message: i18n.T(p.Context, i18n.MsgInvalidID, nil)}
+	}
+	// Как REST API без If-Match, обновление без версии не перезаписывает
+	// сотрудника вслепую: 0 repository понял бы как "без проверки".
+	version := p.Args["version"].(int)
+	if version <= 0 {
+		return nil, &graphQLError{code: "precondition_required", message: i18n.T(p.Context, i18n.MsgVersionRequired, nil)}
+	}
+
+	input := p.Args["input"].(map[string]interface{})
+	emp, err := h.service.UpdateEmployee(p.Context, id, int64(version), domain.UpdateEmployeeRequest{
+		FullName: input["fullName"].(string),
+		Phone:    input["phone"].(string),
+		City:     input["city"].(string),
+	})
+	return h.employeePayload(p.Context, emp, err, "ошибка_обновления_сотрудника")
+}
+
+// employeePayload возвращает ошибки валидации в поле errors, чтобы клиент
+// показал их рядом с полями формы. Остальные ошибки попадают в errors ответа
+// GraphQL с кодом в extensions.code.
+func (h *Handler) employeePayload(ctx context.Context, emp *domain.Employee, err error, logMsg string) (interface{}, error) {
+	var validationErr *service.ValidationErrors
+	if errors.As(err, &validationErr) {
+		h.observeValidationErrors(validationErr)
+		return map[string]interface{}{
+			"employee": nil,
+			"errors":   fieldErrors(ctx, validationErr),
+		}, nil
+	}
+	if err != nil {
+		return nil, h.graphQLServiceError(ctx, err, logMsg)
+	}
+	return map[string]interface{}{
+		"employee": emp,
+		"errors":   []FieldError{},
+	}, nil
+}
@@ -0,0 +1,354 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func doGraphQL(t *testing.T, routes http.Handler, query string, variables map[string]interface{}, headers ...string) graphQLResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/v1/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp graphQLResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return resp
+}
+
+// countingStore считает обращения к хранилищу за сотрудниками по ID.
+type countingStore struct {
+	repository.EmployeeStore
+
+	mu       sync.Mutex
+	getByID  int
+	getByIDs [][]uuid.UUID
+}
+
+func (s *countingStore) GetByID(ctx context.Context, id uuid.UUID) (*domain.Employee, error) {
+	s.mu.Lock()
+	s.getByID++
+	s.mu.Unlock()
+	return s.EmployeeStore.GetByID(ctx, id)
+}
+
+func (s *countingStore) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Employee, error) {
+	s.mu.Lock()
+	s.getByIDs = append(s.getByIDs, ids)
+	s.mu.Unlock()
+	return s.EmployeeStore.GetByIDs(ctx, ids)
+}
+
+func TestGraphQL_Queries(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger(), WithGraphQL(DefaultGraphQLMaxCost)).Routes()
+
+	ctx := context.Background()
+	ivan, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"})
+	require.NoError(t, err)
+	for _, req := range []domain.CreateEmployeeRequest{
+		{FullName: "Петр Петров", Phone: "+77010000002", City: "Астана"},
+		{FullName: "Анна Смирнова", Phone: "+77010000003", City: "Алматы"},
+	} {
+		_, err := svc.CreateEmployee(ctx, req)
+		require.NoError(t, err)
+	}
+
+	resp := doGraphQL(t, routes, `query ($id: ID!) {
+		employee(id: $id) { id fullName phone city createdAt version }
+	}`, map[string]interface{}{"id": ivan.ID.String()})
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{
+		"id": "`+ivan.ID.String()+`",
+		"fullName": "Иван Иванов",
+		"phone": "+77010000001",
+		"city": "Алматы",
+		"createdAt": "`+ivan.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00")+`",
+		"version": 1
+	}`, string(resp.Data["employee"]))
+
+	resp = doGraphQL(t, routes, `{ employee(id: "`+uuid.NewString()+`") { id } }`, nil)
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, "null", string(resp.Data["employee"]), "ненайденный сотрудник - null")
+
+	var page struct {
+		Items []struct {
+			FullName string `json:"fullName"`
+		} `json:"items"`
+		NextCursor *string `json:"nextCursor"`
+	}
+	resp = doGraphQL(t, routes, `{ employees(city: "Алматы", limit: 1) { items { fullName } nextCursor } }`, nil)
+	require.Empty(t, resp.Errors)
+	require.NoError(t, json.Unmarshal(resp.Data["employees"], &page))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Иван Иванов", page.Items[0].FullName)
+	require.NotNil(t, page.NextCursor)
+
+	resp = doGraphQL(t, routes, `query ($cursor: String) {
+		employees(city: "Алматы", limit: 1, cursor: $cursor) { items { fullName } nextCursor }
+	}`, map[string]interface{}{"cursor": *page.NextCursor})
+	require.Empty(t, resp.Errors)
+	require.NoError(t, json.Unmarshal(resp.Data["employees"], &page))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Анна Смирнова", page.Items[0].FullName)
+	assert.Nil(t, page.NextCursor)
+
+	resp = doGraphQL(t, routes, `{ employees(limit: 1000, phonePrefix: "abc") { items { id } } }`, nil, "Accept-Language", "en")
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "Validation failed", resp.Errors[0].Message)
+	assert.Equal(t, "validation_error", resp.Errors[0].Extensions["code"])
+	fields := map[string]interface{}{}
+	for _, e := range resp.Errors[0].Extensions["errors"].([]interface{}) {
+		fieldErr := e.(map[string]interface{})
+		fields[fieldErr["field"].(string)] = fieldErr["code"]
+	}
+	assert.Equal(t, map[string]interface{}{"limit": "range", "phonePrefix": "phone_prefix_format"}, fields)
+
+	resp = doGraphQL(t, routes, `{ employee(id: "42") { id } }`, nil)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "invalid_id", resp.Errors[0].Extensions["code"])
+}
+
+func TestGraphQL_Mutations(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger(), WithGraphQL(DefaultGraphQLMaxCost)).Routes()
+
+	const create = `mutation ($input: CreateEmployeeInput!) {
+		createEmployee(input: $input) { employee { id city version } errors { field code message } }
+	}`
+	type payload struct {
+		Employee *struct {
+			ID      string `json:"id"`
+			City    string `json:"city"`
+			Version int64  `json:"version"`
+		} `json:"employee"`
+		Errors []FieldError `json:"errors"`
+	}
+
+	resp := doGraphQL(t, routes, create, map[string]interface{}{
+		"input": map[string]interface{}{"fullName": "Иван Иванов", "phone": "+77010000001", "city": "Алматы"},
+	})
+	require.Empty(t, resp.Errors)
+	var created payload
+	require.NoError(t, json.Unmarshal(resp.Data["createEmployee"], &created))
+	require.NotNil(t, created.Employee)
+	assert.Empty(t, created.Errors)
+
+	resp = doGraphQL(t, routes, create, map[string]interface{}{
+		"input": map[string]interface{}{"fullName": "И", "phone": "+77010000002", "city": "Алматы"},
+	}, "Accept-Language", "kk")
+	require.Empty(t, resp.Errors, "ошибки валидации возвращаются в payload")
+	var invalid payload
+	require.NoError(t, json.Unmarshal(resp.Data["createEmployee"], &invalid))
+	assert.Nil(t, invalid.Employee)
+	assert.Equal(t, []FieldError{{Field: "fullName", Code: "min_length", Message: "кемінде 2 таңба"}}, invalid.Errors)
+
+	resp = doGraphQL(t, routes, create, map[string]interface{}{
+		"input": map[string]interface{}{"fullName": "Петр Петров", "phone": "+77010000001", "city": "Астана"},
+	})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "duplicate_phone", resp.Errors[0].Extensions["code"])
+	assert.Equal(t, "Телефон уже существует", resp.Errors[0].Message)
+
+	const update = `mutation ($id: ID!, $version: Int!) {
+		updateEmployee(id: $id, version: $version, input: {fullName: "Иван Иванов", phone: "+77010000001", city: "Астана"}) {
+			employee { city version }
+			errors { field }
+		}
+	}`
+	resp = doGraphQL(t, routes, update, map[string]interface{}{"id": created.Employee.ID, "version": created.Employee.Version})
+	require.Empty(t, resp.Errors)
+	var updated payload
+	require.NoError(t, json.Unmarshal(resp.Data["updateEmployee"], &updated))
+	require.NotNil(t, updated.Employee)
+	assert.Equal(t, "Астана", updated.Employee.City)
+	assert.Greater(t, updated.Employee.Version, created.Employee.Version)
+
+	resp = doGraphQL(t, routes, update, map[string]interface{}{"id": created.Employee.ID, "version": created.Employee.Version})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "precondition_failed", resp.Errors[0].Extensions["code"])
+
+	resp = doGraphQL(t, routes, update, map[string]interface{}{"id": uuid.NewString(), "version": 1})
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "not_found", resp.Errors[0].Extensions["code"])
+
+	resp = doGraphQL(t, routes, update, map[string]interface{}{"id": created.Employee.ID, "version": 0}, "Accept-Language", "en")
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "precondition_required", resp.Errors[0].Extensions["code"])
+	assert.Equal(t, "Expected employee version is required", resp.Errors[0].Message)
+
+	resp = doGraphQL(t, routes, update, map[string]interface{}{"id": created.Employee.ID})
+	require.Len(t, resp.Errors, 1, "без version запрос не проходит валидацию схемы")
+	assert.Nil(t, resp.Data)
+}
+
+func TestGraphQL_LoaderBatchesGetByID(t *testing.T) {
+	store := &countingStore{EmployeeStore: repository.NewMemoryEmployeeStore()}
+	svc := service.NewEmployeeService(store)
+	routes := NewHandler(svc, NewLogger(), WithGraphQL(DefaultGraphQLMaxCost)).Routes()
+
+	ctx := context.Background()
+	first, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"})
+	require.NoError(t, err)
+	second, err := svc.CreateEmployee(ctx, domain.CreateEmployeeRequest{FullName: "Петр Петров", Phone: "+77010000002", City: "Астана"})
+	require.NoError(t, err)
+	missing := uuid.New()
+
+	resp := doGraphQL(t, routes, `query ($a: ID!, $b: ID!, $c: ID!) {
+		a: employee(id: $a) { fullName }
+		b: employee(id: $b) { fullName }
+		again: employee(id: $a) { city }
+		c: employee(id: $c) { fullName }
+	}`, map[string]interface{}{"a": first.ID.String(), "b": second.ID.String(), "c": missing.String()})
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"fullName": "Иван Иванов"}`, string(resp.Data["a"]))
+	assert.JSONEq(t, `{"fullName": "Петр Петров"}`, string(resp.Data["b"]))
+	assert.JSONEq(t, `{"city": "Алматы"}`, string(resp.Data["again"]))
+	assert.Equal(t, "null", string(resp.Data["c"]))
+
+	assert.Zero(t, store.getByID)
+	require.Len(t, store.getByIDs, 1, "все employee(id) загружаются одним запросом")
+	assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID, missing}, store.getByIDs[0])
+}
+
+func TestGraphQL_CostLimit(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger(), WithGraphQL(DefaultGraphQLMaxCost)).Routes()
+
+	const fullPage = `employees(limit: 100) { items { id fullName phone city createdAt updatedAt version } nextCursor }`
+
+	resp := doGraphQL(t, routes, `{ `+fullPage+` }`, nil)
+	assert.Empty(t, resp.Errors, "страница максимального размера укладывается в предел")
+
+	resp = doGraphQL(t, routes, `{ first: `+fullPage+` second: `+fullPage+` }`, nil, "Accept-Language", "en")
+	require.Len(t, resp.Errors, 1)
+	assert.Nil(t, resp.Data)
+	assert.Equal(t, "query_too_complex", resp.Errors[0].Extensions["code"])
+	assert.Equal(t, float64(DefaultGraphQLMaxCost), resp.Errors[0].Extensions["max"])
+	assert.Equal(t, "Query cost exceeds the limit of 1000", resp.Errors[0].Message)
+
+	done := make(chan graphQLResponse, 1)
+	go func() {
+		done <- doGraphQL(t, routes, nestedFragmentsQuery(30), nil)
+	}()
+	select {
+	case resp = <-done:
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "query_too_complex", resp.Errors[0].Extensions["code"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("оценка стоимости вложенных фрагментов не завершилась")
+	}
+}
+
+// nestedFragmentsQuery строит запрос, в котором каждый фрагмент дважды
+// включает следующий: без кэша стоимости фрагментов обход занял бы 2^depth шагов.
+func nestedFragmentsQuery(depth int) string {
+	var b strings.Builder
+	b.WriteString("{ employee(id: \"1\") { ...f0 } }\n")
+	for i := 0; i < depth; i++ {
+		fmt.Fprintf(&b, "fragment f%d on Employee { id ...f%d ...f%d }\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, "fragment f%d on Employee { id }\n", depth)
+	return b.String()
+}
+
+func TestQueryCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		want      int
+	}{
+		{"одно поле", `{ employee(id: "1") { id } }`, "", nil, 2},
+		{"страница по умолчанию", `{ employees { items { id } } }`, "", nil, 1 + 20*2},
+		{"limit в аргументе", `{ employees(limit: 5) { items { id fullName } nextCursor } }`, "", nil, 1 + 5*4},
+		{"limit в переменной", `query ($n: Int) { employees(limit: $n) { items { id } } }`, "", map[string]interface{}{"n": float64(50)}, 1 + 50*2},
+		{"limit больше максимума", `{ employees(limit: 100000) { items { id } } }`, "", nil, 1 + 100*2},
+		{"фрагменты", `{ employees(limit: 2) { ...page } } fragment page on EmployeeList { items { ... on Employee { id city } } }`, "", nil, 1 + 2*3},
+		{"насыщение на пределе", `{ a: employees(limit: 100) { items { id fullName phone city } } b: employees(limit: 100) { items { id fullName phone city } } }`, "", nil, DefaultGraphQLMaxCost + 1},
+		{"вложенные фрагменты", nestedFragmentsQuery(30), "", nil, DefaultGraphQLMaxCost + 1},
+		{"выбранная операция", `query a { employee(id: "1") { id } } query b { employees(limit: 1) { items { id } } }`, "b", map[string]interface{}{}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, queryCost(doc, tt.operation, tt.variables, DefaultGraphQLMaxCost))
+		})
+	}
+}
+
+func TestGraphQL_RequestErrors(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger(), WithGraphQL(DefaultGraphQLMaxCost)).Routes()
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{"GET", http.MethodGet, "", "", http.StatusMethodNotAllowed, "method_not_allowed"},
+		{"не JSON", http.MethodPost, "text/plain", `{}`, http.StatusBadRequest, "invalid_content_type"},
+		{"битый JSON", http.MethodPost, "application/json", `{`, http.StatusBadRequest, "invalid_json"},
+		{"без query", http.MethodPost, "application/json", `{"query": " "}`, http.StatusBadRequest, "graphql_query_required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/v1/graphql", bytes.NewBufferString(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, req)
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCode, resp.Code)
+		})
+	}
+
+	resp := doGraphQL(t, routes, `{ employees { nope } }`, nil)
+	require.NotEmpty(t, resp.Errors, "ошибка валидации запроса - статус 200 и errors")
+	assert.Nil(t, resp.Data)
+}
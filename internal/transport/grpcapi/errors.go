@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"employees-api/internal/authz"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+	"employees-api/internal/transport"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// protoFields переводит имена полей из ValidationErrors (как в JSON REST API)
+// в имена полей сообщений protobuf.
+var protoFields = map[string]string{
+	"fullName":    "full_name",
+	"phonePrefix": "phone_prefix",
+	"cursor":      "page_token",
+	"limit":       "page_size",
+}
+
+// serviceError переводит ошибку сервисного слоя в статус gRPC по тем же
+// правилам, что и respondServiceError в REST API.
+func (s *Server) serviceError(ctx context.Context, err error, logMsg string) error {
+	var validationErr *service.ValidationErrors
+	switch {
+	case errors.As(err, &validationErr):
+		return validationStatus(ctx, validationErr)
+	case errors.Is(err, repository.ErrDuplicatePhone):
+		return status.Error(codes.AlreadyExists, i18n.T(ctx, i18n.MsgDuplicatePhone, nil))
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return status.Error(codes.FailedPrecondition, i18n.T(ctx, i18n.MsgPreconditionFailed, nil))
+	case errors.Is(err, authz.ErrForbidden):
+		return status.Error(codes.PermissionDenied, i18n.T(ctx, i18n.MsgForbidden, nil))
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, i18n.T(ctx, i18n.MsgEmployeeNotFound, nil))
+	case errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		s.logger.ErrorContext(ctx, logMsg,
+			slog.String(transport.LogKeyErrorType, "внутренняя"),
+			slog.Any(transport.LogKeyError, err),
+		)
+		return status.Error(codes.Internal, i18n.T(ctx, i18n.MsgInternalError, nil))
+	}
+}
+
+// validationStatus возвращает INVALID_ARGUMENT с google.rpc.BadRequest, в
+// котором каждое нарушенное правило - отдельный FieldViolation с текстом на
+// языке клиента.
+func validationStatus(ctx context.Context, validationErr *service.ValidationErrors) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, e := range validationErr.Errors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, fieldViolation(ctx, e.Field, e.Code, e.Params))
+	}
+	return withBadRequest(status.New(codes.InvalidArgument, i18n.T(ctx, i18n.MsgValidationError, nil)), badRequest)
+}
+
+// invalidID - ошибка разбора идентификатора сотрудника в поле field.
+func invalidID(ctx context.Context, field string) error {
+	badRequest := &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{fieldViolation(ctx, field, i18n.Invalid, nil)},
+	}
+	return withBadRequest(status.New(codes.InvalidArgument, i18n.T(ctx, i18n.MsgInvalidID, nil)), badRequest)
+}
+
+// versionRequired - изменение без ожидаемой версии. Как REST API без If-Match,
+// gRPC не перезаписывает сотрудника вслепую.
+func versionRequired(ctx context.Context) error {
+	return status.Error(codes.FailedPrecondition, i18n.T(ctx, i18n.MsgVersionRequired, nil))
+}
+
+func fieldViolation(ctx context.Context, field string, code i18n.MessageID, params i18n.Params) *errdetails.BadRequest_FieldViolation {
+	if name, ok := protoFields[field]; ok {
+		field = name
+	}
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: i18n.T(ctx, code, params),
+	}
+}
+
+func withBadRequest(st *status.Status, badRequest *errdetails.BadRequest) error {
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
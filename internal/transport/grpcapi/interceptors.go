@@ -0,0 +1,140 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/i18n"
+	"employees-api/internal/service"
+	"employees-api/internal/transport"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// recoverInterceptor превращает панику обработчика в INTERNAL, как
+// recoverMiddleware в REST API.
+func (s *Server) recoverInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			s.logger.ErrorContext(ctx, "восстановление_паники",
+				slog.String(transport.LogKeyErrorType, "паника"),
+				slog.String(transport.LogKeyMethod, info.FullMethod),
+				slog.Any(transport.LogKeyPanic, p),
+			)
+			err = status.Error(codes.Internal, i18n.T(ctx, i18n.MsgInternalError, nil))
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// check выполняет проверку перед обработчиком и может дополнить контекст.
+// Одни и те же проверки ставятся и на unary, и на потоковые вызовы через
+// unaryCheck и streamCheck, чтобы reflection и другие потоки не обходили их.
+type check func(ctx context.Context, fullMethod string) (context.Context, error)
+
+func unaryCheck(c check) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := c(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamCheck(c check) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := c(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream подменяет контекст потока на дополненный проверками.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// language выбирает язык сообщений об ошибках по метаданным accept-language.
+func (s *Server) language(ctx context.Context, _ string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	lang := i18n.Negotiate(strings.Join(md.Get("accept-language"), ","))
+	return i18n.WithLanguage(ctx, lang), nil
+}
+
+// authenticate пропускает только вызовы с валидным bearer токеном или API
+// ключом в метаданных authorization или x-api-key. Проверка здоровья доступна
+// без аутентификации. Без WithAuthentication и WithAPIKeys не вызывается.
+func (s *Server) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if isHealthCheck(fullMethod) {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := first(md.Get("authorization"))
+
+	if key, ok := apiKeyFromMetadata(md, authorization); ok && s.apiKeys != nil {
+		apiKey, err := s.apiKeys.AuthenticateAPIKey(ctx, key)
+		if err != nil {
+			if !errors.Is(err, service.ErrInvalidAPIKey) {
+				s.logger.ErrorContext(ctx, "ошибка_проверки_api_ключа",
+					slog.String(transport.LogKeyErrorType, "внутренняя"),
+					slog.Any(transport.LogKeyError, err),
+				)
+				return nil, status.Error(codes.Internal, i18n.T(ctx, i18n.MsgInternalError, nil))
+			}
+			return nil, status.Error(codes.Unauthenticated, i18n.T(ctx, i18n.MsgInvalidAPIKey, nil))
+		}
+		return auth.WithIdentity(ctx, transport.APIKeyIdentity(apiKey)), nil
+	}
+
+	token, ok := auth.BearerToken(authorization)
+	if !ok || s.auth == nil {
+		return nil, status.Error(codes.Unauthenticated, i18n.T(ctx, i18n.MsgAuthenticationRequired, nil))
+	}
+
+	identity, err := s.auth.Authenticate(ctx, token)
+	if err != nil {
+		s.logger.InfoContext(ctx, "ошибка_аутентификации", slog.Any(transport.LogKeyError, err))
+		return nil, status.Error(codes.Unauthenticated, i18n.T(ctx, i18n.MsgInvalidToken, nil))
+	}
+
+	return auth.WithIdentity(ctx, identity), nil
+}
+
+func isHealthCheck(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+grpc_health_v1.Health_ServiceDesc.ServiceName+"/")
+}
+
+func apiKeyFromMetadata(md metadata.MD, authorization string) (string, bool) {
+	if key := strings.TrimSpace(first(md.Get("x-api-key"))); key != "" {
+		return key, true
+	}
+	scheme, key, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "ApiKey") {
+		return "", false
+	}
+	key = strings.TrimSpace(key)
+	return key, key != ""
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
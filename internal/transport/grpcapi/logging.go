@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"employees-api/internal/database"
+	"employees-api/internal/redact"
+	"employees-api/internal/transport"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// loggingInterceptor пишет строку лога и метрики на каждый unary вызов, как
+// loggingMiddleware в REST API. Ид запроса берется из метаданных x-request-id,
+// иначе совпадает с ид трассировки, и возвращается в заголовке x-request-id.
+func (s *Server) loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	ctx, queryStats := database.WithQueryStats(ctx)
+	ctx = redact.NewContext(ctx)
+	requestID := grpcRequestID(ctx)
+	ctx = transport.ContextWithRequestID(ctx, requestID)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+
+	resp, err := handler(ctx, req)
+
+	duration := time.Since(start)
+	code := status.Code(err).String()
+	attrs := []slog.Attr{
+		slog.String(transport.LogKeyMethod, info.FullMethod),
+		slog.String(transport.LogKeyGRPCCode, code),
+		slog.Int64(transport.LogKeyLatencyMs, duration.Milliseconds()),
+		slog.String(transport.LogKeyRemoteAddr, peerIP(ctx)),
+	}
+	if stats := queryStats.Snapshot(); stats.Count > 0 {
+		attrs = append(attrs,
+			slog.Float64(transport.LogKeyDBTimeMs, durationMs(stats.Total)),
+			slog.Int(transport.LogKeyDBQueries, stats.Count),
+			slog.String(transport.LogKeySlowestQuery, stats.SlowestName),
+			slog.Float64(transport.LogKeySlowestQueryMs, durationMs(stats.Slowest)),
+		)
+	}
+
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "grpc_запрос", attrs...)
+	s.metrics.ObserveGRPCRequest(info.FullMethod, code, duration)
+	return resp, err
+}
+
+func grpcRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if requestID := first(md.Get("x-request-id")); requestID != "" {
+		return requestID
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return uuid.New().String()
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
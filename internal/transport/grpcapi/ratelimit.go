@@ -0,0 +1,101 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/i18n"
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/transport"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// WithRateLimit ограничивает частоту вызовов теми же правилами, что и REST API.
+// Вызов сопоставляется с правилами как "POST /<сервис>/<метод>", например
+// "POST /employees.v1.EmployeesService/CreateEmployee". Ключи корзин совпадают с
+// REST API, поэтому при общем store клиент расходует один лимит на оба
+// транспорта. perIP ограничивает адрес до аутентификации, как WithIPRateLimit.
+func WithRateLimit(store ratelimit.Store, rules *ratelimit.Rules, perIP ratelimit.Limit) Option {
+	return func(s *Server) {
+		s.rateLimitStore = store
+		s.rateLimits = rules
+		s.ipRateLimit = perIP
+	}
+}
+
+// limitIP ограничивает вызовы с одного адреса до аутентификации: вызовы с
+// неверным токеном или API ключом тоже расходуют корзину адреса. Проверка
+// здоровья и методы без ограничения (off) не учитываются.
+func (s *Server) limitIP(ctx context.Context, fullMethod string) (context.Context, error) {
+	if isHealthCheck(fullMethod) || s.rateLimits.Match(http.MethodPost, fullMethod).Limit.Unlimited() {
+		return ctx, nil
+	}
+	return ctx, s.takeRateLimit(ctx, "ip|"+peerIP(ctx), s.ipRateLimit, "ip")
+}
+
+// limitClient ограничивает вызовы клиента по правилу метода. Стоит после
+// authenticate, чтобы различать клиентов по API ключу и subject токена.
+func (s *Server) limitClient(ctx context.Context, fullMethod string) (context.Context, error) {
+	if isHealthCheck(fullMethod) {
+		return ctx, nil
+	}
+	rule := s.rateLimits.Match(http.MethodPost, fullMethod)
+	if rule.Limit.Unlimited() {
+		return ctx, nil
+	}
+	return ctx, s.takeRateLimit(ctx, rule.String()+"|"+rateLimitClient(ctx), rule.Limit, rule.String())
+}
+
+// takeRateLimit берет токен из корзины key. Если токена нет, возвращает
+// RESOURCE_EXHAUSTED и передает в заголовке retry-after секунды до следующего токена.
+func (s *Server) takeRateLimit(ctx context.Context, key string, limit ratelimit.Limit, label string) error {
+	result, err := s.rateLimitStore.Take(ctx, key, limit)
+	if err != nil {
+		// Недоступное хранилище лимитов не должно останавливать API.
+		s.logger.WarnContext(ctx, "ошибка_ограничения_частоты", slog.Any(transport.LogKeyError, err))
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+
+	s.metrics.ObserveRateLimited(label)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds())))))
+	return status.Error(codes.ResourceExhausted, i18n.T(ctx, i18n.MsgRateLimited, nil))
+}
+
+// rateLimitClient возвращает ключ клиента так же, как REST API: API ключ,
+// subject токена или IP.
+func rateLimitClient(ctx context.Context) string {
+	if identity, ok := auth.IdentityFromContext(ctx); ok && identity != nil {
+		if identity.Scopes != nil {
+			return identity.Subject
+		}
+		return "sub:" + identity.Subject
+	}
+	return "ip:" + peerIP(ctx)
+}
+
+// peerIP - адрес клиента без порта. Заголовки прокси не учитываются: gRPC
+// клиенты внутренние и подключаются напрямую.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,214 @@
+// Package grpcapi - gRPC транспорт для EmployeesService из api/employees/v1.
+// Вызовы идут в тот же service.Employees, что и у REST API, поэтому
+// валидация, права и ошибки у обоих транспортов совпадают.
+package grpcapi
+
+import (
+	"context"
+
+	employeesv1 "employees-api/api/employees/v1"
+	"employees-api/internal/auth"
+	"employees-api/internal/domain"
+	"employees-api/internal/metrics"
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/service"
+	"employees-api/internal/transport"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Server struct {
+	employeesv1.UnimplementedEmployeesServiceServer
+
+	employees service.Employees
+	logger    *transport.Logger
+	auth      *auth.Authenticator
+	apiKeys   service.APIKeys
+	health    *health.Server
+	metrics   *metrics.Metrics
+
+	rateLimitStore ratelimit.Store
+	rateLimits     *ratelimit.Rules
+	ipRateLimit    ratelimit.Limit
+}
+
+type Option func(*Server)
+
+// WithAuthentication требует bearer токен в метаданных authorization.
+func WithAuthentication(authenticator *auth.Authenticator) Option {
+	return func(s *Server) {
+		s.auth = authenticator
+	}
+}
+
+// WithAPIKeys принимает API ключ в метаданных x-api-key или
+// "authorization: ApiKey <ключ>" и включает аутентификацию.
+func WithAPIKeys(apiKeys service.APIKeys) Option {
+	return func(s *Server) {
+		s.apiKeys = apiKeys
+	}
+}
+
+// WithMetrics учитывает unary вызовы и отказы по ограничению частоты в метриках Prometheus.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+func NewServer(employees service.Employees, logger *transport.Logger, opts ...Option) *Server {
+	s := &Server{
+		employees: employees,
+		logger:    logger,
+		health:    health.NewServer(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.health.SetServingStatus(employeesv1.EmployeesService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	return s
+}
+
+// GRPCServer создает grpc.Server с EmployeesService, проверкой здоровья
+// (grpc.health.v1) и server reflection. Вызовы трассируются через otelgrpc.
+// Аутентификация и ограничение частоты действуют и на потоковые вызовы
+// (reflection, Health/Watch), лог и метрики пишутся только для unary.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	checks := []check{s.language}
+	if s.rateLimits != nil && !s.ipRateLimit.Unlimited() {
+		checks = append(checks, s.limitIP)
+	}
+	if s.auth != nil || s.apiKeys != nil {
+		checks = append(checks, s.authenticate)
+	}
+	if s.rateLimits != nil {
+		checks = append(checks, s.limitClient)
+	}
+
+	unary := []grpc.UnaryServerInterceptor{s.loggingInterceptor, s.recoverInterceptor}
+	stream := make([]grpc.StreamServerInterceptor, 0, len(checks))
+	for _, c := range checks {
+		unary = append(unary, unaryCheck(c))
+		stream = append(stream, streamCheck(c))
+	}
+
+	gs := grpc.NewServer(append(opts,
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)...)
+	employeesv1.RegisterEmployeesServiceServer(gs, s)
+	grpc_health_v1.RegisterHealthServer(gs, s.health)
+	reflection.Register(gs)
+	return gs
+}
+
+// Shutdown переводит все сервисы в NOT_SERVING, чтобы балансировщик перестал
+// присылать новые вызовы до остановки сервера.
+func (s *Server) Shutdown() {
+	s.health.Shutdown()
+}
+
+func (s *Server) CreateEmployee(ctx context.Context, req *employeesv1.CreateEmployeeRequest) (*employeesv1.Employee, error) {
+	emp, err := s.employees.CreateEmployee(ctx, domain.CreateEmployeeRequest{
+		FullName: req.GetFullName(),
+		Phone:    req.GetPhone(),
+		City:     req.GetCity(),
+	})
+	if err != nil {
+		return nil, s.serviceError(ctx, err, "ошибка_создания_сотрудника")
+	}
+	return employeeToProto(emp), nil
+}
+
+func (s *Server) GetEmployee(ctx context.Context, req *employeesv1.GetEmployeeRequest) (*employeesv1.Employee, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidID(ctx, "id")
+	}
+
+	emp, err := s.employees.GetEmployeeByID(ctx, id)
+	if err != nil {
+		return nil, s.serviceError(ctx, err, "ошибка_получения_сотрудника")
+	}
+	return employeeToProto(emp), nil
+}
+
+func (s *Server) ListEmployees(ctx context.Context, req *employeesv1.ListEmployeesRequest) (*employeesv1.ListEmployeesResponse, error) {
+	list, err := s.employees.ListEmployees(ctx, domain.ListEmployeesRequest{
+		City:        req.GetCity(),
+		PhonePrefix: req.GetPhonePrefix(),
+		Sort:        req.GetSort(),
+		Cursor:      req.GetPageToken(),
+		Limit:       int(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, s.serviceError(ctx, err, "ошибка_получения_списка_сотрудников")
+	}
+
+	resp := &employeesv1.ListEmployeesResponse{
+		Employees: make([]*employeesv1.Employee, 0, len(list.Items)),
+	}
+	for i := range list.Items {
+		resp.Employees = append(resp.Employees, employeeToProto(&list.Items[i]))
+	}
+	if list.NextCursor != nil {
+		resp.NextPageToken = *list.NextCursor
+	}
+	return resp, nil
+}
+
+func (s *Server) UpdateEmployee(ctx context.Context, req *employeesv1.UpdateEmployeeRequest) (*employeesv1.Employee, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidID(ctx, "id")
+	}
+
+	if req.GetVersion() <= 0 {
+		return nil, versionRequired(ctx)
+	}
+
+	emp, err := s.employees.UpdateEmployee(ctx, id, req.GetVersion(), domain.UpdateEmployeeRequest{
+		FullName: req.GetFullName(),
+		Phone:    req.GetPhone(),
+		City:     req.GetCity(),
+	})
+	if err != nil {
+		return nil, s.serviceError(ctx, err, "ошибка_обновления_сотрудника")
+	}
+	return employeeToProto(emp), nil
+}
+
+func (s *Server) DeleteEmployee(ctx context.Context, req *employeesv1.DeleteEmployeeRequest) (*employeesv1.DeleteEmployeeResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, invalidID(ctx, "id")
+	}
+
+	if req.GetVersion() <= 0 {
+		return nil, versionRequired(ctx)
+	}
+
+	if err := s.employees.DeleteEmployee(ctx, id, req.GetVersion()); err != nil {
+		return nil, s.serviceError(ctx, err, "ошибка_удаления_сотрудника")
+	}
+	return &employeesv1.DeleteEmployeeResponse{}, nil
+}
+
+func employeeToProto(emp *domain.Employee) *employeesv1.Employee {
+	return &employeesv1.Employee{
+		Id:         emp.ID.String(),
+		FullName:   emp.FullName,
+		Phone:      emp.Phone,
+		City:       emp.City,
+		CreateTime: timestamppb.New(emp.CreatedAt),
+		UpdateTime: timestamppb.New(emp.UpdatedAt),
+		Version:    emp.Version,
+	}
+}
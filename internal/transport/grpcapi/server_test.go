@@ -0,0 +1,423 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	employeesv1 "employees-api/api/employees/v1"
+	"employees-api/internal/auth/authtest"
+	"employees-api/internal/authz"
+	"employees-api/internal/domain"
+	"employees-api/internal/metrics"
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+	"employees-api/internal/tracing"
+	"employees-api/internal/transport"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial запускает сервер в памяти и возвращает соединение с ним.
+func dial(t *testing.T, employees service.Employees, opts ...Option) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, NewServer(employees, transport.NewLogger(), opts...))
+}
+
+func dialServer(t *testing.T, s *Server) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	gs := s.GRPCServer()
+	go gs.Serve(listener)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestServer_CRUD(t *testing.T) {
+	ctx := context.Background()
+	client := employeesv1.NewEmployeesServiceClient(dial(t, service.NewEmployeeService(repository.NewMemoryEmployeeStore())))
+
+	created, err := client.CreateEmployee(ctx, &employeesv1.CreateEmployeeRequest{
+		FullName: "  Иван Иванов ",
+		Phone:    "+77010000001",
+		City:     "Алматы",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Иванов", created.FullName)
+	assert.NotEmpty(t, created.Id)
+	assert.False(t, created.CreateTime.AsTime().IsZero())
+
+	got, err := client.GetEmployee(ctx, &employeesv1.GetEmployeeRequest{Id: created.Id})
+	require.NoError(t, err)
+	assert.Equal(t, created.Id, got.Id)
+	assert.Equal(t, created.Version, got.Version)
+
+	_, err = client.CreateEmployee(ctx, &employeesv1.CreateEmployeeRequest{FullName: "Петр Петров", Phone: "+77010000002", City: "Астана"})
+	require.NoError(t, err)
+
+	page, err := client.ListEmployees(ctx, &employeesv1.ListEmployeesRequest{PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Employees, 1)
+	assert.Equal(t, created.Id, page.Employees[0].Id)
+	require.NotEmpty(t, page.NextPageToken)
+
+	page, err = client.ListEmployees(ctx, &employeesv1.ListEmployeesRequest{PageSize: 1, PageToken: page.NextPageToken})
+	require.NoError(t, err)
+	require.Len(t, page.Employees, 1)
+	assert.Equal(t, "Петр Петров", page.Employees[0].FullName)
+	assert.Empty(t, page.NextPageToken)
+
+	filtered, err := client.ListEmployees(ctx, &employeesv1.ListEmployeesRequest{City: "Астана"})
+	require.NoError(t, err)
+	require.Len(t, filtered.Employees, 1)
+
+	updated, err := client.UpdateEmployee(ctx, &employeesv1.UpdateEmployeeRequest{
+		Id:       created.Id,
+		FullName: "Иван Иванов",
+		Phone:    "+77010000001",
+		City:     "Шымкент",
+		Version:  created.Version,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Шымкент", updated.City)
+	assert.Greater(t, updated.Version, created.Version)
+
+	_, err = client.UpdateEmployee(ctx, &employeesv1.UpdateEmployeeRequest{
+		Id:       created.Id,
+		FullName: "Иван Иванов",
+		Phone:    "+77010000001",
+		City:     "Алматы",
+		Version:  created.Version,
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "устаревшая версия")
+
+	_, err = client.DeleteEmployee(ctx, &employeesv1.DeleteEmployeeRequest{Id: created.Id, Version: updated.Version})
+	require.NoError(t, err)
+
+	_, err = client.GetEmployee(ctx, &employeesv1.GetEmployeeRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_Errors(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	existing, err := svc.CreateEmployee(context.Background(), domain.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"})
+	require.NoError(t, err)
+	client := employeesv1.NewEmployeesServiceClient(dial(t, svc))
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		call           func(ctx context.Context) error
+		wantCode       codes.Code
+		wantMessage    string
+		wantViolations map[string]string
+	}{
+		{
+			name: "валидация",
+			call: func(ctx context.Context) error {
+				_, err := client.CreateEmployee(ctx, &employeesv1.CreateEmployeeRequest{FullName: "И", Phone: "123", City: "Алматы"})
+				return err
+			},
+			wantCode:    codes.InvalidArgument,
+			wantMessage: "Ошибка валидации",
+			wantViolations: map[string]string{
+				"full_name": "минимум 2 символа",
+				"phone":     "формат E.164 (+[1-15 цифр])",
+			},
+		},
+		{
+			name:           "валидация на английском",
+			acceptLanguage: "en",
+			call: func(ctx context.Context) error {
+				_, err := client.ListEmployees(ctx, &employeesv1.ListEmployeesRequest{PageSize: 101, PhonePrefix: "abc"})
+				return err
+			},
+			wantCode:    codes.InvalidArgument,
+			wantMessage: "Validation failed",
+			wantViolations: map[string]string{
+				"page_size":    "from 1 to 100",
+				"phone_prefix": "prefix in E.164 format (+[1-15 digits])",
+			},
+		},
+		{
+			name: "невалидный ID",
+			call: func(ctx context.Context) error {
+				_, err := client.GetEmployee(ctx, &employeesv1.GetEmployeeRequest{Id: "not-a-uuid"})
+				return err
+			},
+			wantCode:       codes.InvalidArgument,
+			wantMessage:    "Невалидный ID",
+			wantViolations: map[string]string{"id": "невалидное значение"},
+		},
+		{
+			name: "дубликат телефона",
+			call: func(ctx context.Context) error {
+				_, err := client.CreateEmployee(ctx, &employeesv1.CreateEmployeeRequest{FullName: "Петр Петров", Phone: "+77010000001", City: "Астана"})
+				return err
+			},
+			wantCode:    codes.AlreadyExists,
+			wantMessage: "Телефон уже существует",
+		},
+		{
+			name: "не найден",
+			call: func(ctx context.Context) error {
+				_, err := client.DeleteEmployee(ctx, &employeesv1.DeleteEmployeeRequest{Id: "00000000-0000-0000-0000-000000000001", Version: 1})
+				return err
+			},
+			wantCode:    codes.NotFound,
+			wantMessage: "Сотрудник не найден",
+		},
+		{
+			name:           "без версии",
+			acceptLanguage: "en",
+			call: func(ctx context.Context) error {
+				_, err := client.UpdateEmployee(ctx, &employeesv1.UpdateEmployeeRequest{Id: existing.ID.String(), FullName: "Иван Иванов", Phone: "+77010000001", City: "Астана"})
+				return err
+			},
+			wantCode:    codes.FailedPrecondition,
+			wantMessage: "Expected employee version is required",
+		},
+		{
+			name:           "версия изменилась",
+			acceptLanguage: "kk",
+			call: func(ctx context.Context) error {
+				_, err := client.DeleteEmployee(ctx, &employeesv1.DeleteEmployeeRequest{Id: existing.ID.String(), Version: existing.Version + 1})
+				return err
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.acceptLanguage != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", tt.acceptLanguage)
+			}
+
+			st := status.Convert(tt.call(ctx))
+			assert.Equal(t, tt.wantCode, st.Code())
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, st.Message())
+			}
+
+			violations := map[string]string{}
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, v := range badRequest.FieldViolations {
+						violations[v.Field] = v.Description
+					}
+				}
+			}
+			if tt.wantViolations == nil {
+				assert.Empty(t, violations)
+				return
+			}
+			assert.Equal(t, tt.wantViolations, violations)
+		})
+	}
+}
+
+func TestServer_HealthAndReflection(t *testing.T) {
+	ctx := context.Background()
+	keys := service.NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+	conn := dial(t, service.NewEmployeeService(repository.NewMemoryEmployeeStore()), WithAPIKeys(keys))
+
+	for _, name := range []string{"", employeesv1.EmployeesService_ServiceDesc.ServiceName} {
+		resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: name})
+		require.NoError(t, err, "проверка здоровья доступна без аутентификации")
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+	}
+
+	listServices := func(ctx context.Context) (*grpc_reflection_v1.ServerReflectionResponse, error) {
+		stream, err := grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+			MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+		}))
+		return stream.Recv()
+	}
+
+	_, err := listServices(ctx)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "reflection требует аутентификации")
+
+	issued, err := keys.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{Name: "grpcurl", Scopes: []string{"employees.list"}})
+	require.NoError(t, err)
+	resp, err := listServices(metadata.AppendToOutgoingContext(ctx, "x-api-key", issued.Key))
+	require.NoError(t, err)
+
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.Name)
+	}
+	assert.Contains(t, services, employeesv1.EmployeesService_ServiceDesc.ServiceName)
+	assert.Contains(t, services, grpc_health_v1.Health_ServiceDesc.ServiceName)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	server := authtest.NewServer(t)
+	policy, err := authz.ParsePolicy([]byte(`{"roles": {
+		"hr_admin": [{"actions": ["employees.*"]}]
+	}}`))
+	require.NoError(t, err)
+
+	svc := authz.NewEmployeeService(service.NewEmployeeService(repository.NewMemoryEmployeeStore()), authz.Static(policy))
+	keys := service.NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+	issued, err := keys.CreateAPIKey(context.Background(), domain.CreateAPIKeyRequest{Name: "payroll", Scopes: []string{"employees.list"}})
+	require.NoError(t, err)
+
+	client := employeesv1.NewEmployeesServiceClient(dial(t, svc, WithAuthentication(server.Authenticator()), WithAPIKeys(keys)))
+	create := &employeesv1.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"}
+
+	tests := []struct {
+		name     string
+		metadata []string
+		wantCode codes.Code
+	}{
+		{"без учетных данных", nil, codes.Unauthenticated},
+		{"невалидный токен", []string{"authorization", "Bearer invalid"}, codes.Unauthenticated},
+		{"невалидный ключ", []string{"x-api-key", "invalid"}, codes.Unauthenticated},
+		{"действие вне scopes ключа", []string{"x-api-key", issued.Key}, codes.PermissionDenied},
+		{"роль без прав", []string{"authorization", "Bearer " + server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"role": "manager"}))}, codes.PermissionDenied},
+		{"hr_admin", []string{"authorization", "Bearer " + server.SignRS256(t, authtest.Claims("user-1", jwt.MapClaims{"role": "hr_admin"}))}, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tt.metadata...)
+			_, err := client.CreateEmployee(ctx, create)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "ApiKey "+issued.Key)
+	list, err := client.ListEmployees(ctx, &employeesv1.ListEmployeesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.Employees, 1)
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	keys := service.NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+	issued, err := keys.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{Name: "payroll", Scopes: []string{"employees.list"}})
+	require.NoError(t, err)
+
+	rules := ratelimit.NewRules(ratelimit.Limit{Requests: 1, Period: time.Minute, Burst: 1},
+		ratelimit.Rule{Method: "*", Route: "/employees.v1.EmployeesService/GetEmployee", Limit: ratelimit.Limit{}},
+	)
+	conn := dial(t, service.NewEmployeeService(repository.NewMemoryEmployeeStore()),
+		WithAPIKeys(keys),
+		WithRateLimit(ratelimit.NewMemoryStore(), rules, ratelimit.Limit{Requests: 1, Period: time.Minute, Burst: 3}),
+	)
+	client := employeesv1.NewEmployeesServiceClient(conn)
+
+	keyCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", issued.Key)
+	_, err = client.ListEmployees(keyCtx, &employeesv1.ListEmployeesRequest{})
+	require.NoError(t, err)
+
+	var header metadata.MD
+	_, err = client.ListEmployees(keyCtx, &employeesv1.ListEmployeesRequest{}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "корзина ключа пуста")
+	assert.Equal(t, []string{"60"}, header.Get("retry-after"))
+
+	_, err = client.GetEmployee(keyCtx, &employeesv1.GetEmployeeRequest{Id: uuid.NewString()})
+	assert.Equal(t, codes.NotFound, status.Code(err), "метод без ограничения не расходует корзину адреса")
+
+	badCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", "invalid")
+	_, err = client.ListEmployees(badCtx, &employeesv1.ListEmployeesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.ListEmployees(badCtx, &employeesv1.ListEmployeesRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "неверные ключи расходуют корзину адреса")
+
+	resp, err := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err, "проверка здоровья не ограничивается")
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestServer_LogsMetricsAndTraces(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tracing.NewProvider(recorder, 1))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	var buf bytes.Buffer
+	m := metrics.New()
+	logger := transport.NewLogger(transport.WithLogOutput(&buf), transport.WithFieldLanguage(transport.LogLanguageEN))
+	client := employeesv1.NewEmployeesServiceClient(dialServer(t,
+		NewServer(service.NewEmployeeService(repository.NewMemoryEmployeeStore()), logger, WithMetrics(m))))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	var header metadata.MD
+	_, err := client.CreateEmployee(ctx, &employeesv1.CreateEmployeeRequest{FullName: "Иван Иванов", Phone: "+77010000001", City: "Алматы"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{traceID}, header.Get("x-request-id"))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	_, err = client.GetEmployee(ctx, &employeesv1.GetEmployeeRequest{Id: "bad"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+	assert.Equal(t, "/employees.v1.EmployeesService/CreateEmployee", entries[0][transport.LogKeyMethod])
+	assert.Equal(t, "OK", entries[0][transport.LogKeyGRPCCode])
+	assert.Equal(t, traceID, entries[0][transport.LogKeyTraceID])
+	assert.Equal(t, "InvalidArgument", entries[1][transport.LogKeyGRPCCode])
+	assert.Equal(t, "req-1", entries[1][transport.LogKeyRequestID])
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `employees_api_grpc_requests_total{code="InvalidArgument",method="/employees.v1.EmployeesService/GetEmployee"} 1`)
+
+	var spans []string
+	for _, span := range recorder.Ended() {
+		spans = append(spans, span.Name())
+		if span.Name() == "employees.v1.EmployeesService/CreateEmployee" {
+			assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+			assert.True(t, span.Parent().IsRemote())
+		}
+	}
+	assert.Contains(t, spans, "employees.v1.EmployeesService/CreateEmployee")
+	assert.Contains(t, spans, "EmployeeService.CreateEmployee")
+}
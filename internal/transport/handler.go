@@ -4,11 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 
+	"employees-api/internal/auth"
+	"employees-api/internal/authz"
 	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/metrics"
+	"employees-api/internal/ratelimit"
 	"employees-api/internal/repository"
 	"employees-api/internal/service"
 
@@ -16,53 +26,233 @@ import (
 )
 
 type Handler struct {
-	service *service.EmployeeService
-	logger  *Logger
+	service        service.Employees
+	logger         *Logger
+	idempotency    *repository.IdempotencyRepository
+	idempotencyTTL time.Duration
+	imports        service.Imports
+	importMaxBytes int64
+	exportTimeout  time.Duration
+	metrics        *metrics.Metrics
+	auth           *auth.Authenticator
+	authExempt     map[string]bool
+	apiKeys        service.APIKeys
+	rateLimits     *ratelimit.Rules
+	rateLimitStore ratelimit.Store
+	ipRateLimit    ratelimit.Limit
+	trustedProxies []netip.Prefix
+	problemDetails bool
+	graphQLMaxCost int
 }
 
-func NewHandler(svc *service.EmployeeService, logger *Logger) *Handler {
-	return &Handler{
+type HandlerOption func(*Handler)
+
+// WithIdempotency включает поддержку заголовка Idempotency-Key для создания сотрудников.
+func WithIdempotency(store *repository.IdempotencyRepository, ttl time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.idempotency = store
+		h.idempotencyTTL = ttl
+	}
+}
+
+// WithImports включает эндпоинты импорта сотрудников из CSV и XLSX.
+func WithImports(imports service.Imports, maxBytes int64) HandlerOption {
+	return func(h *Handler) {
+		h.imports = imports
+		h.importMaxBytes = maxBytes
+	}
+}
+
+// WithExportTimeout ограничивает длительность выгрузки; 0 снимает ограничение.
+func WithExportTimeout(timeout time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.exportTimeout = timeout
+	}
+}
+
+// WithMetrics включает сбор метрик запросов и эндпоинт /metrics.
+func WithMetrics(m *metrics.Metrics) HandlerOption {
+	return func(h *Handler) {
+		h.metrics = m
+	}
+}
+
+// WithAuthentication требует JWT bearer токен для всех путей, кроме exemptPaths.
+// Пути сравниваются целиком, например "/v1/healthz".
+func WithAuthentication(authenticator *auth.Authenticator, exemptPaths ...string) HandlerOption {
+	return func(h *Handler) {
+		h.auth = authenticator
+		h.authExempt = make(map[string]bool, len(exemptPaths))
+		for _, path := range exemptPaths {
+			h.authExempt[path] = true
+		}
+	}
+}
+
+// WithAPIKeys принимает API ключи в заголовках "Authorization: ApiKey <ключ>"
+// и X-API-Key и включает эндпоинты управления ключами /v1/admin/api-keys.
+// Пути из WithAuthentication освобождаются и от проверки API ключа.
+func WithAPIKeys(keys service.APIKeys) HandlerOption {
+	return func(h *Handler) {
+		h.apiKeys = keys
+	}
+}
+
+// WithRateLimit ограничивает частоту запросов каждого клиента по rules.
+// X-Forwarded-For учитывается только от адресов из trustedProxies.
+func WithRateLimit(store ratelimit.Store, rules *ratelimit.Rules, trustedProxies ...netip.Prefix) HandlerOption {
+	return func(h *Handler) {
+		h.rateLimitStore = store
+		h.rateLimits = rules
+		h.trustedProxies = trustedProxies
+	}
+}
+
+// WithIPRateLimit добавляет к WithRateLimit лимит на адрес клиента, который
+// действует до аутентификации, в том числе на запросы с неверными ключами.
+func WithIPRateLimit(limit ratelimit.Limit) HandlerOption {
+	return func(h *Handler) {
+		h.ipRateLimit = limit
+	}
+}
+
+func NewHandler(svc service.Employees, logger *Logger, opts ...HandlerOption) *Handler {
+	h := &Handler{
 		service: svc,
 		logger:  logger,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 type ErrorResponse struct {
 	Code    string                 `json:"code"`
 	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
+	Errors  []FieldError           `json:"errors,omitempty"`
+}
+
+// FieldError описывает нарушенное правило валидации поля: code и params
+// стабильны, message переведен на язык запроса.
+type FieldError struct {
+	Field   string      `json:"field"`
+	Code    string      `json:"code"`
+	Params  i18n.Params `json:"params,omitempty"`
+	Message string      `json:"message"`
 }
 
 func (h *Handler) Routes() http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/v1/employees", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.ListEmployees(w, r)
+		case http.MethodPost:
+			h.idempotent(h.CreateEmployee)(w, r)
+		default:
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/v1/employees:batch", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
-			h.CreateEmployee(w, r)
+			h.idempotent(h.CreateEmployeesBatch)(w, r)
 		} else {
-			respondError(w, ErrorResponse{
-				Code:    "method_not_allowed",
-				Message: "Метод не поддерживается",
-			}, http.StatusMethodNotAllowed)
+			h.respondMethodNotAllowed(w, r)
 		}
 	})
 
-	mux.HandleFunc("/v1/employees/", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/v1/employees:export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			h.ExportEmployees(w, r)
+		} else {
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/v1/employees/search", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
+			h.SearchEmployees(w, r)
+		} else {
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/v1/employees/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":restore") {
+			setRoute(r, "/v1/employees/{id}:restore")
+			if r.Method == http.MethodPost {
+				h.RestoreEmployee(w, r)
+			} else {
+				h.respondMethodNotAllowed(w, r)
+			}
+			return
+		}
+
+		setRoute(r, "/v1/employees/{id}")
+		switch r.Method {
+		case http.MethodGet:
 			h.GetEmployee(w, r)
+		case http.MethodPut:
+			h.UpdateEmployee(w, r)
+		case http.MethodPatch:
+			h.PatchEmployee(w, r)
+		case http.MethodDelete:
+			h.DeleteEmployee(w, r)
+		default:
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/v1/admin/employees/", func(w http.ResponseWriter, r *http.Request) {
+		setRoute(r, "/v1/admin/employees/{id}")
+		if r.Method == http.MethodDelete {
+			h.PurgeEmployee(w, r)
 		} else {
-			respondError(w, ErrorResponse{
-				Code:    "method_not_allowed",
-				Message: "Метод не поддерживается",
-			}, http.StatusMethodNotAllowed)
+			h.respondMethodNotAllowed(w, r)
 		}
 	})
 
+	if h.imports != nil {
+		h.importRoutes(mux)
+	}
+
+	if h.apiKeys != nil {
+		h.apiKeyRoutes(mux)
+	}
+
+	if h.graphQLMaxCost > 0 {
+		h.graphQLRoutes(mux)
+	}
+
 	mux.HandleFunc("/v1/healthz", h.HealthCheck)
+	mux.HandleFunc("/v1/openapi.json", h.GetOpenAPI)
 
-	handler := h.requestIDMiddleware(mux)
+	if h.metrics != nil {
+		mux.Handle("/metrics", h.metrics.Handler())
+	}
+
+	// recoverMiddleware стоит внутри loggingMiddleware: запрос с паникой попадает
+	// в лог и метрики со статусом 500, а значение паники маскируется с учетом
+	// данных запроса.
+	handler := h.recoverMiddleware(mux)
+	if h.rateLimits != nil {
+		handler = h.rateLimitMiddleware(handler)
+	}
+	if h.auth != nil || h.apiKeys != nil {
+		handler = h.authMiddleware(handler)
+	}
+	if h.rateLimits != nil && !h.ipRateLimit.Unlimited() {
+		handler = h.ipRateLimitMiddleware(handler)
+	}
+	handler = h.routeMiddleware(mux, handler)
+	handler = h.languageMiddleware(handler)
 	handler = h.loggingMiddleware(handler)
-	handler = h.recoverMiddleware(handler)
+	handler = h.requestIDMiddleware(handler)
+	handler = h.tracingMiddleware(handler)
 
 	return handler
 }
@@ -72,9 +262,9 @@ func (h *Handler) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	if r.Header.Get("Content-Type") != "application/json" {
-		respondError(w, ErrorResponse{
+		h.respondError(w, r, ErrorResponse{
 			Code:    "invalid_content_type",
-			Message: "Content-Type должен быть application/json",
+			Message: i18n.T(r.Context(), i18n.MsgContentTypeJSON, nil),
 		}, http.StatusBadRequest)
 		return
 	}
@@ -84,48 +274,21 @@ func (h *Handler) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 	dec.DisallowUnknownFields()
 
 	if err := dec.Decode(&req); err != nil {
-		respondError(w, ErrorResponse{
+		h.respondError(w, r, ErrorResponse{
 			Code:    "invalid_json",
-			Message: "Невалидный JSON",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
 		}, http.StatusBadRequest)
 		return
 	}
 
 	emp, err := h.service.CreateEmployee(ctx, req)
 	if err != nil {
-		var validationErr *service.ValidationErrors
-		if errors.As(err, &validationErr) {
-			details := make(map[string]interface{})
-			for _, e := range validationErr.Errors {
-				details[e.Field] = e.Message
-			}
-			respondError(w, ErrorResponse{
-				Code:    "validation_error",
-				Message: "Ошибка валидации",
-				Details: details,
-			}, http.StatusUnprocessableEntity)
-			return
-		}
-
-		if errors.Is(err, repository.ErrDuplicatePhone) {
-			respondError(w, ErrorResponse{
-				Code:    "duplicate_phone",
-				Message: "Телефон уже существует",
-			}, http.StatusConflict)
-			return
-		}
-
-		h.logger.Error("ошибка_создания_сотрудника", map[string]interface{}{
-			"тип_ошибки": "внутренняя",
-		})
-		respondError(w, ErrorResponse{
-			Code:    "internal_error",
-			Message: "Внутренняя ошибка сервера",
-		}, http.StatusInternalServerError)
+		h.respondServiceError(w, r, err, "ошибка_создания_сотрудника")
 		return
 	}
 
 	w.Header().Set("Location", "/v1/employees/"+emp.ID.String())
+	setETag(w, emp)
 	respondJSON(w, emp, http.StatusCreated)
 }
 
@@ -133,37 +296,241 @@ func (h *Handler) GetEmployee(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	idStr := r.URL.Path[len("/v1/employees/"):]
-	id, err := uuid.Parse(idStr)
+	id, ok := h.parseEmployeeID(w, r)
+	if !ok {
+		return
+	}
+
+	emp, err := h.service.GetEmployeeByID(ctx, id)
 	if err != nil {
-		respondError(w, ErrorResponse{
-			Code:    "invalid_id",
-			Message: "Невалидный ID",
+		h.respondServiceError(w, r, err, "ошибка_получения_сотрудника")
+		return
+	}
+
+	setETag(w, emp)
+	if notModified(r, emp) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	respondJSON(w, emp, http.StatusOK)
+}
+
+func (h *Handler) UpdateEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parseEmployeeID(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/json" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_content_type",
+			Message: i18n.T(r.Context(), i18n.MsgContentTypeJSON, nil),
 		}, http.StatusBadRequest)
 		return
 	}
 
-	emp, err := h.service.GetEmployeeByID(ctx, id)
+	var req domain.UpdateEmployeeRequest
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1024*1024))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_json",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	version, ok := h.requireIfMatch(ctx, w, r, id)
+	if !ok {
+		return
+	}
+
+	emp, err := h.service.UpdateEmployee(ctx, id, version, req)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_обновления_сотрудника")
+		return
+	}
+
+	setETag(w, emp)
+	respondJSON(w, emp, http.StatusOK)
+}
+
+func (h *Handler) PatchEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parseEmployeeID(w, r)
+	if !ok {
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/merge-patch+json" && contentType != "application/json" {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_content_type",
+			Message: i18n.T(r.Context(), i18n.MsgContentTypeMergePatch, nil),
+		}, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	patch, validationErrs, err := decodeEmployeePatch(io.LimitReader(r.Body, 1024*1024))
+	if err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_json",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+	if validationErrs.HasErrors() {
+		h.respondValidationError(w, r, validationErrs)
+		return
+	}
+
+	version, ok := h.requireIfMatch(ctx, w, r, id)
+	if !ok {
+		return
+	}
+
+	emp, err := h.service.PatchEmployee(ctx, id, version, patch)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_обновления_сотрудника")
+		return
+	}
+
+	setETag(w, emp)
+	respondJSON(w, emp, http.StatusOK)
+}
+
+func (h *Handler) DeleteEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parseEmployeeID(w, r)
+	if !ok {
+		return
+	}
+
+	version, ok := h.requireIfMatch(ctx, w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEmployee(ctx, id, version); err != nil {
+		h.respondServiceError(w, r, err, "ошибка_удаления_сотрудника")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) RestoreEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parsePathID(w, r, "/v1/employees/", ":restore")
+	if !ok {
+		return
+	}
+
+	emp, err := h.service.RestoreEmployee(ctx, id)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			respondError(w, ErrorResponse{
-				Code:    "not_found",
-				Message: "Сотрудник не найден",
-			}, http.StatusNotFound)
+		h.respondServiceError(w, r, err, "ошибка_восстановления_сотрудника")
+		return
+	}
+
+	setETag(w, emp)
+	respondJSON(w, emp, http.StatusOK)
+}
+
+func (h *Handler) PurgeEmployee(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parsePathID(w, r, "/v1/admin/employees/", "")
+	if !ok {
+		return
+	}
+
+	if err := h.service.PurgeEmployee(ctx, id); err != nil {
+		h.respondServiceError(w, r, err, "ошибка_окончательного_удаления_сотрудника")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListEmployees(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	req := domain.ListEmployeesRequest{
+		City:        query.Get("city"),
+		PhonePrefix: query.Get("phonePrefix"),
+		Sort:        query.Get("sort"),
+		Cursor:      query.Get("cursor"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			validationErrs := &service.ValidationErrors{}
+			validationErrs.Add("limit", i18n.Integer, nil)
+			h.respondValidationError(w, r, validationErrs)
 			return
 		}
+		req.Limit = limit
+	}
 
-		h.logger.Error("ошибка_получения_сотрудника", map[string]interface{}{
-			"тип_ошибки": "внутренняя",
-		})
-		respondError(w, ErrorResponse{
-			Code:    "internal_error",
-			Message: "Внутренняя ошибка сервера",
-		}, http.StatusInternalServerError)
+	list, err := h.service.ListEmployees(ctx, req)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_получения_списка_сотрудников")
 		return
 	}
 
-	respondJSON(w, emp, http.StatusOK)
+	respondJSON(w, list, http.StatusOK)
+}
+
+func (h *Handler) SearchEmployees(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	req := domain.SearchEmployeesRequest{
+		Query: query.Get("q"),
+	}
+
+	validationErrs := &service.ValidationErrors{}
+	if v := query.Get("threshold"); v != "" {
+		threshold, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			validationErrs.Add("threshold", i18n.Number, nil)
+		}
+		req.Threshold = &threshold
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			validationErrs.Add("limit", i18n.Integer, nil)
+		}
+		req.Limit = limit
+	}
+	if validationErrs.HasErrors() {
+		h.respondValidationError(w, r, validationErrs)
+		return
+	}
+
+	result, err := h.service.SearchEmployees(ctx, req)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_поиска_сотрудников")
+		return
+	}
+
+	respondJSON(w, result, http.StatusOK)
 }
 
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
@@ -171,12 +538,13 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	if err := h.service.HealthCheck(ctx); err != nil {
-		h.logger.Error("проверка_здоровья_провалена", map[string]interface{}{
-			"тип_ошибки": "база_данных_недоступна",
-		})
-		respondError(w, ErrorResponse{
+		h.logger.ErrorContext(ctx, "проверка_здоровья_провалена",
+			slog.String(LogKeyErrorType, "база_данных_недоступна"),
+			slog.Any(LogKeyError, err),
+		)
+		h.respondError(w, r, ErrorResponse{
 			Code:    "unhealthy",
-			Message: "Сервис недоступен",
+			Message: i18n.T(r.Context(), i18n.MsgUnhealthy, nil),
 		}, http.StatusServiceUnavailable)
 		return
 	}
@@ -184,14 +552,144 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, map[string]string{"status": "ok"}, http.StatusOK)
 }
 
-func respondJSON(w http.ResponseWriter, data interface{}, status int) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+func (h *Handler) parseEmployeeID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	return h.parsePathID(w, r, "/v1/employees/", "")
+}
+
+func (h *Handler) parsePathID(w http.ResponseWriter, r *http.Request, prefix, suffix string) (uuid.UUID, bool) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), suffix)
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_id",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidID, nil),
+		}, http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+	return id, true
 }
 
-func respondError(w http.ResponseWriter, err ErrorResponse, status int) {
+// decodeEmployeePatch разбирает JSON Merge Patch (RFC 7396). Все поля сотрудника
+// обязательные, поэтому null (удаление поля) считается ошибкой валидации.
+func decodeEmployeePatch(body io.Reader) (domain.EmployeePatch, *service.ValidationErrors, error) {
+	var patch domain.EmployeePatch
+	validationErrs := &service.ValidationErrors{}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return patch, nil, err
+	}
+	if raw == nil {
+		return patch, nil, errors.New("патч должен быть JSON объектом")
+	}
+
+	fields := map[string]**string{
+		"fullName": &patch.FullName,
+		"phone":    &patch.Phone,
+		"city":     &patch.City,
+	}
+
+	for key, value := range raw {
+		dst, ok := fields[key]
+		if !ok {
+			return patch, nil, fmt.Errorf("неизвестное поле %q", key)
+		}
+		if string(value) == "null" {
+			validationErrs.Add(key, i18n.Required, nil)
+			continue
+		}
+		var v string
+		if err := json.Unmarshal(value, &v); err != nil {
+			return patch, nil, err
+		}
+		*dst = &v
+	}
+
+	return patch, validationErrs, nil
+}
+
+func (h *Handler) respondMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	h.respondError(w, r, ErrorResponse{
+		Code:    "method_not_allowed",
+		Message: i18n.T(r.Context(), i18n.MsgMethodNotAllowed, nil),
+	}, http.StatusMethodNotAllowed)
+}
+
+func (h *Handler) respondServiceError(w http.ResponseWriter, r *http.Request, err error, logMsg string) {
+	var validationErr *service.ValidationErrors
+	switch {
+	case errors.As(err, &validationErr):
+		h.respondValidationError(w, r, validationErr)
+	case errors.Is(err, repository.ErrDuplicatePhone):
+		h.respondError(w, r, ErrorResponse{
+			Code:    "duplicate_phone",
+			Message: i18n.T(r.Context(), i18n.MsgDuplicatePhone, nil),
+		}, http.StatusConflict)
+	case errors.Is(err, repository.ErrVersionMismatch):
+		h.respondPreconditionFailed(w, r)
+	case errors.Is(err, authz.ErrForbidden):
+		h.respondError(w, r, ErrorResponse{
+			Code:    "forbidden",
+			Message: i18n.T(r.Context(), i18n.MsgForbidden, nil),
+		}, http.StatusForbidden)
+	case errors.Is(err, repository.ErrNotFound):
+		h.respondError(w, r, ErrorResponse{
+			Code:    "not_found",
+			Message: i18n.T(r.Context(), i18n.MsgEmployeeNotFound, nil),
+		}, http.StatusNotFound)
+	default:
+		h.logger.ErrorContext(r.Context(), logMsg,
+			slog.String(LogKeyErrorType, "внутренняя"),
+			slog.Any(LogKeyError, err),
+		)
+		h.respondError(w, r, ErrorResponse{
+			Code:    "internal_error",
+			Message: i18n.T(r.Context(), i18n.MsgInternalError, nil),
+		}, http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) respondValidationError(w http.ResponseWriter, r *http.Request, validationErr *service.ValidationErrors) {
+	h.observeValidationErrors(validationErr)
+	h.respondError(w, r, validationErrorResponse(r, validationErr), http.StatusUnprocessableEntity)
+}
+
+func (h *Handler) observeValidationErrors(validationErr *service.ValidationErrors) {
+	for _, e := range validationErr.Errors {
+		h.metrics.ObserveValidationFailure(e.Field)
+	}
+}
+
+func validationErrorResponse(r *http.Request, validationErr *service.ValidationErrors) ErrorResponse {
+	fieldErrs := fieldErrors(r.Context(), validationErr)
+	details := make(map[string]interface{}, len(fieldErrs))
+	for _, e := range fieldErrs {
+		details[e.Field] = e.Message
+	}
+	return ErrorResponse{
+		Code:    "validation_error",
+		Message: i18n.T(r.Context(), i18n.MsgValidationError, nil),
+		Details: details,
+		Errors:  fieldErrs,
+	}
+}
+
+// fieldErrors переводит нарушенные правила на язык из ctx.
+func fieldErrors(ctx context.Context, validationErr *service.ValidationErrors) []FieldError {
+	fieldErrs := make([]FieldError, 0, len(validationErr.Errors))
+	for _, e := range validationErr.Errors {
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   e.Field,
+			Code:    string(e.Code),
+			Params:  e.Params,
+			Message: i18n.T(ctx, e.Code, e.Params),
+		})
+	}
+	return fieldErrs
+}
+
+func respondJSON(w http.ResponseWriter, data interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(err)
+	json.NewEncoder(w).Encode(data)
 }
@@ -0,0 +1,78 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError_AcceptLanguage(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger()).Routes()
+
+	tests := []struct {
+		acceptLanguage string
+		wantLanguage   string
+		wantMessage    string
+		wantFullName   string
+	}{
+		{"", "ru", "Ошибка валидации", "минимум 2 символа"},
+		{"kk-KZ,kk;q=0.9", "kk", "Валидация қатесі", "кемінде 2 таңба"},
+		{"en-US,en;q=0.9,ru;q=0.8", "en", "Validation failed", "at least 2 characters"},
+		{"de", "ru", "Ошибка валидации", "минимум 2 символа"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantLanguage+" "+tt.acceptLanguage, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/employees",
+				strings.NewReader(`{"fullName":"И","phone":"+77010000001","city":"Алматы"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Equal(t, tt.wantLanguage, rec.Header().Get("Content-Language"))
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept-Language")
+
+			var resp ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, "validation_error", resp.Code)
+			assert.Equal(t, tt.wantMessage, resp.Message)
+			assert.Equal(t, tt.wantFullName, resp.Details["fullName"])
+
+			require.Len(t, resp.Errors, 1)
+			assert.Equal(t, "fullName", resp.Errors[0].Field)
+			assert.Equal(t, "min_length", resp.Errors[0].Code)
+			assert.Equal(t, float64(2), resp.Errors[0].Params["min"])
+			assert.Equal(t, tt.wantFullName, resp.Errors[0].Message)
+		})
+	}
+}
+
+func TestErrorResponse_AcceptLanguage(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger()).Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/employees/not-a-uuid", nil)
+	req.Header.Set("Accept-Language", "en")
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, "invalid_id", resp.Code)
+	assert.Equal(t, "Invalid ID", resp.Message)
+}
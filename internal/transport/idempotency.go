@@ -0,0 +1,177 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/i18n"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyLockTimeout - через сколько незавершенный ключ считается
+// брошенным: запрос, занявший ключ, упал вместе с процессом и не освободил
+// его. Заведомо больше времени обработки запроса на создание.
+const idempotencyLockTimeout = time.Minute
+
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// idempotent оборачивает создающий обработчик: повтор запроса с тем же
+// Idempotency-Key и телом возвращает сохраненный ответ вместо повторного выполнения.
+// Ключи разных клиентов не пересекаются: сохраненный ответ получает только тот
+// клиент, чей запрос его создал.
+func (h *Handler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" || h.idempotency == nil {
+			next(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			h.respondError(w, r, ErrorResponse{
+				Code:    "invalid_idempotency_key",
+				Message: i18n.T(r.Context(), i18n.MsgInvalidIdempotencyKey, nil),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, 5*1024*1024))
+		if err != nil {
+			h.respondError(w, r, ErrorResponse{
+				Code:    "invalid_json",
+				Message: i18n.T(r.Context(), i18n.MsgInvalidJSON, nil),
+			}, http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(r, body)
+		key = idempotencyStorageKey(r, key)
+
+		record, reserved, err := h.idempotency.Reserve(r.Context(), key, fingerprint, h.idempotencyTTL, idempotencyLockTimeout)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "ошибка_резервирования_ключа_идемпотентности",
+				slog.String(LogKeyErrorType, "внутренняя"),
+				slog.Any(LogKeyError, err),
+			)
+			h.respondError(w, r, ErrorResponse{
+				Code:    "internal_error",
+				Message: i18n.T(r.Context(), i18n.MsgInternalError, nil),
+			}, http.StatusInternalServerError)
+			return
+		}
+
+		if !reserved {
+			switch {
+			case record.Fingerprint != fingerprint:
+				h.respondError(w, r, ErrorResponse{
+					Code:    "idempotency_key_reused",
+					Message: i18n.T(r.Context(), i18n.MsgIdempotencyKeyReused, nil),
+				}, http.StatusUnprocessableEntity)
+			case !record.Completed():
+				w.Header().Set("Retry-After", "1")
+				h.respondError(w, r, ErrorResponse{
+					Code:    "idempotency_key_in_progress",
+					Message: i18n.T(r.Context(), i18n.MsgIdempotencyKeyInProgress, nil),
+				}, http.StatusConflict)
+			default:
+				for name, value := range record.Headers {
+					w.Header().Set(name, value)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.Body)
+			}
+			return
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				h.idempotency.Release(ctx, key)
+				panic(p)
+			}
+		}()
+
+		rec := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next(rec, r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// 5xx не сохраняются: клиент должен иметь возможность повторить запрос.
+		if rec.statusCode >= http.StatusInternalServerError {
+			err = h.idempotency.Release(ctx, key)
+		} else {
+			headers := make(map[string]string)
+			for _, name := range replayedHeaders {
+				if value := rec.Header().Get(name); value != "" {
+					headers[name] = value
+				}
+			}
+			err = h.idempotency.Complete(ctx, key, rec.statusCode, headers, rec.body.Bytes())
+		}
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "ошибка_сохранения_ключа_идемпотентности",
+				slog.String(LogKeyErrorType, "внутренняя"),
+				slog.Any(LogKeyError, err),
+			)
+		}
+	}
+}
+
+// idempotencyStorageKey добавляет к ключу клиента хэш его subject, как
+// rateLimitClient различая API ключи и токены. Без аутентификации ключ
+// остается как есть.
+func idempotencyStorageKey(r *http.Request, key string) string {
+	identity, ok := auth.IdentityFromContext(r.Context())
+	if !ok || identity == nil {
+		return key
+	}
+
+	client := "sub:" + identity.Subject
+	if identity.Scopes != nil {
+		client = identity.Subject
+	}
+	hash := sha256.Sum256([]byte(client))
+	return hex.EncodeToString(hash[:]) + ":" + key
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(p []byte) (int, error) {
+	rw.body.Write(p)
+	return rw.ResponseWriter.Write(p)
+}
+
+func (rw *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,31 @@
+package transport
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"employees-api/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyStorageKey(t *testing.T) {
+	key := func(identity *auth.Identity) string {
+		r := httptest.NewRequest("POST", "/v1/employees", nil)
+		if identity != nil {
+			r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+		}
+		return idempotencyStorageKey(r, "onboarding-42")
+	}
+
+	anonymous := key(nil)
+	alice := key(&auth.Identity{Subject: "alice"})
+	bob := key(&auth.Identity{Subject: "bob"})
+	apiKey := key(&auth.Identity{Subject: "alice", Scopes: []string{}})
+
+	assert.Equal(t, "onboarding-42", anonymous, "без аутентификации ключ не меняется")
+	assert.Equal(t, alice, key(&auth.Identity{Subject: "alice"}), "ключ одного клиента стабилен")
+	assert.NotEqual(t, alice, bob, "ключи разных клиентов не пересекаются")
+	assert.NotEqual(t, alice, apiKey, "токен и API ключ с одинаковым subject - разные клиенты")
+	assert.NotEqual(t, anonymous, alice)
+}
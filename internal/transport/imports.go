@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/i18n"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+)
+
+type ImportJobResponse struct {
+	*domain.ImportJob
+	ReportURL string `json:"reportUrl,omitempty"`
+}
+
+// newImportJobResponse переводит ошибку задачи на язык запроса, как
+// GetImportReport переводит ошибки строк.
+func newImportJobResponse(ctx context.Context, job *domain.ImportJob) ImportJobResponse {
+	if job.ErrorCode != "" {
+		job.Error = i18n.T(ctx, i18n.MessageID(job.ErrorCode), job.ErrorParams)
+	}
+	resp := ImportJobResponse{ImportJob: job}
+	if job.Status == domain.ImportStatusCompleted || job.Status == domain.ImportStatusFailed {
+		resp.ReportURL = "/v1/imports/" + job.ID.String() + "/report"
+	}
+	return resp
+}
+
+func (h *Handler) importRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/v1/imports", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			h.StartImport(w, r)
+		} else {
+			h.respondMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/v1/imports/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			h.respondMethodNotAllowed(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/report") {
+			setRoute(r, "/v1/imports/{id}/report")
+			h.GetImportReport(w, r)
+		} else {
+			setRoute(r, "/v1/imports/{id}")
+			h.GetImportJob(w, r)
+		}
+	})
+}
+
+// StartImport принимает multipart/form-data с полями file, format (csv|xlsx,
+// по умолчанию из расширения файла), dryRun и mapping (JSON с именами столбцов).
+func (h *Handler) StartImport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.importMaxBytes)
+	if err := r.ParseMultipartForm(h.importMaxBytes); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.respondError(w, r, ErrorResponse{
+				Code:    "file_too_large",
+				Message: i18n.T(r.Context(), i18n.MsgFileTooLarge, nil),
+				Details: map[string]interface{}{"maxBytes": h.importMaxBytes},
+			}, http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_multipart",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidMultipart, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_multipart",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidMultipart, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.respondError(w, r, ErrorResponse{
+			Code:    "invalid_multipart",
+			Message: i18n.T(r.Context(), i18n.MsgInvalidMultipart, nil),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	req := domain.ImportRequest{
+		Format: domain.ImportFormat(strings.ToLower(r.FormValue("format"))),
+		Data:   data,
+	}
+	if req.Format == "" {
+		req.Format = domain.ImportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), "."))
+	}
+
+	validationErrs := &service.ValidationErrors{}
+	if v := r.FormValue("dryRun"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			validationErrs.Add("dryRun", i18n.Boolean, nil)
+		}
+		req.DryRun = dryRun
+	}
+	if v := r.FormValue("mapping"); v != "" {
+		dec := json.NewDecoder(strings.NewReader(v))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req.Mapping); err != nil {
+			validationErrs.Add("mapping", i18n.ImportMapping, nil)
+		}
+	}
+	if validationErrs.HasErrors() {
+		h.respondValidationError(w, r, validationErrs)
+		return
+	}
+
+	job, err := h.imports.StartImport(ctx, req)
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_запуска_импорта")
+		return
+	}
+
+	w.Header().Set("Location", "/v1/imports/"+job.ID.String())
+	respondJSON(w, newImportJobResponse(r.Context(), job), http.StatusAccepted)
+}
+
+func (h *Handler) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parsePathID(w, r, "/v1/imports/", "")
+	if !ok {
+		return
+	}
+
+	job, err := h.imports.GetImportJob(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		h.respondImportNotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.respondServiceError(w, r, err, "ошибка_получения_задачи_импорта")
+		return
+	}
+
+	respondJSON(w, newImportJobResponse(r.Context(), job), http.StatusOK)
+}
+
+// GetImportReport отдает ошибки по строкам в CSV, который можно открыть в Excel
+// рядом с исходным файлом.
+func (h *Handler) GetImportReport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id, ok := h.parsePathID(w, r, "/v1/imports/", "/report")
+	if !ok {
+		return
+	}
+
+	rowErrors, err := h.imports.GetImportReport(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			h.respondImportNotFound(w, r)
+			return
+		}
+		if errors.Is(err, service.ErrImportInProgress) {
+			w.Header().Set("Retry-After", "5")
+			h.respondError(w, r, ErrorResponse{
+				Code:    "import_in_progress",
+				Message: i18n.T(r.Context(), i18n.MsgImportInProgress, nil),
+			}, http.StatusConflict)
+			return
+		}
+		h.respondServiceError(w, r, err, "ошибка_получения_отчета_импорта")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="import-`+id.String()+`-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"row", "field", "message"})
+	for _, e := range rowErrors {
+		message := e.Message
+		if e.Code != "" {
+			message = i18n.T(r.Context(), i18n.MessageID(e.Code), e.Params)
+		}
+		cw.Write([]string{strconv.Itoa(e.Row), e.Field, message})
+	}
+	cw.Flush()
+}
+
+func (h *Handler) respondImportNotFound(w http.ResponseWriter, r *http.Request) {
+	h.respondError(w, r, ErrorResponse{
+		Code:    "not_found",
+		Message: i18n.T(r.Context(), i18n.MsgImportJobNotFound, nil),
+	}, http.StatusNotFound)
+}
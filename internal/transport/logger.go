@@ -1,41 +1,287 @@
 package transport
 
 import (
-	"encoding/json"
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
 	"time"
+
+	"employees-api/internal/redact"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+
+	LogLanguageRU = "ru"
+	LogLanguageEN = "en"
+)
+
+// Ключи полей лога. Код всегда пишет английские ключи, при LogLanguageRU
+// redactHandler заменяет их на русские.
+const (
+	LogKeyRequestID      = "request_id"
+	LogKeyTraceID        = "trace_id"
+	LogKeyMethod         = "method"
+	LogKeyPath           = "path"
+	LogKeyStatus         = "status"
+	LogKeyGRPCCode       = "grpc_code"
+	LogKeyLatencyMs      = "latency_ms"
+	LogKeyRemoteAddr     = "remote_addr"
+	LogKeyDBTimeMs       = "db_time_ms"
+	LogKeyDBQueries      = "db_queries"
+	LogKeySlowestQuery   = "db_slowest_query"
+	LogKeySlowestQueryMs = "db_slowest_query_ms"
+	LogKeyError          = "error"
+	LogKeyErrorType      = "error_type"
+	LogKeyExported       = "exported"
+	LogKeyDirectory      = "directory"
+	LogKeyCount          = "count"
+	LogKeyPort           = "port"
+	LogKeyTimeoutMs      = "timeout_ms"
+	LogKeyDeleted        = "deleted"
+	LogKeyPanic          = "panic"
+	LogKeyAborted        = "aborted"
+	LogKeyLevel          = "level"
+	LogKeyTime           = "ts"
 )
 
+var russianLogKeys = map[string]string{
+	LogKeyRequestID:      "ид_запроса",
+	LogKeyTraceID:        "ид_трассировки",
+	LogKeyMethod:         "метод",
+	LogKeyPath:           "путь",
+	LogKeyStatus:         "статус",
+	LogKeyGRPCCode:       "код_grpc",
+	LogKeyLatencyMs:      "задержка_мс",
+	LogKeyRemoteAddr:     "адрес",
+	LogKeyDBTimeMs:       "время_бд_мс",
+	LogKeyDBQueries:      "запросов_бд",
+	LogKeySlowestQuery:   "медленный_запрос",
+	LogKeySlowestQueryMs: "медленный_запрос_мс",
+	LogKeyError:          "ошибка",
+	LogKeyErrorType:      "тип_ошибки",
+	LogKeyExported:       "выгружено",
+	LogKeyDirectory:      "директория",
+	LogKeyCount:          "количество",
+	LogKeyPort:           "порт",
+	LogKeyTimeoutMs:      "таймаут_мс",
+	LogKeyDeleted:        "удалено",
+	LogKeyPanic:          "паника",
+	LogKeyAborted:        "прерван",
+}
+
+// DefaultClearFields — поля, которые пишутся без маскирования: в них не
+// бывает ФИО и телефонов. Остальные строковые значения проходят через redact.Text.
+var DefaultClearFields = []string{
+	LogKeyRequestID,
+	LogKeyTraceID,
+	LogKeyMethod,
+	LogKeyPath,
+	LogKeyGRPCCode,
+	LogKeyRemoteAddr,
+	LogKeySlowestQuery,
+	LogKeyErrorType,
+	LogKeyDirectory,
+	LogKeyPort,
+}
+
+// Logger — структурированный логгер на log/slog. Идентификаторы запроса и
+// трассировки добавляются из контекста автоматически, поэтому в обработчиках
+// следует использовать методы с суффиксом Context.
 type Logger struct {
-	logger *log.Logger
+	*slog.Logger
+}
+
+type loggerOptions struct {
+	level       slog.Leveler
+	format      string
+	language    string
+	output      io.Writer
+	clearFields []string
+}
+
+type LoggerOption func(*loggerOptions)
+
+// WithLogLevel задает минимальный уровень записей. По умолчанию info.
+func WithLogLevel(level slog.Leveler) LoggerOption {
+	return func(o *loggerOptions) {
+		o.level = level
+	}
 }
 
-func NewLogger() *Logger {
-	return &Logger{
-		logger: log.New(os.Stdout, "", 0),
+// WithLogFormat выбирает формат вывода: LogFormatJSON (по умолчанию) или LogFormatText.
+func WithLogFormat(format string) LoggerOption {
+	return func(o *loggerOptions) {
+		o.format = format
 	}
 }
 
-func (l *Logger) Info(msg string, fields map[string]interface{}) {
-	l.log("info", msg, fields)
+// WithFieldLanguage выбирает язык ключей полей: LogLanguageRU (по умолчанию) или LogLanguageEN.
+func WithFieldLanguage(language string) LoggerOption {
+	return func(o *loggerOptions) {
+		o.language = language
+	}
+}
+
+// WithLogOutput перенаправляет вывод. По умолчанию os.Stdout.
+func WithLogOutput(w io.Writer) LoggerOption {
+	return func(o *loggerOptions) {
+		o.output = w
+	}
 }
 
-func (l *Logger) Error(msg string, fields map[string]interface{}) {
-	l.log("error", msg, fields)
+// WithClearFields заменяет DefaultClearFields. Ключи указываются по-английски
+// независимо от WithFieldLanguage.
+func WithClearFields(keys ...string) LoggerOption {
+	return func(o *loggerOptions) {
+		o.clearFields = keys
+	}
 }
 
-func (l *Logger) log(level, msg string, fields map[string]interface{}) {
-	entry := map[string]interface{}{
-		"ts":    time.Now().UTC().Format(time.RFC3339Nano),
-		"level": level,
-		"msg":   msg,
+func NewLogger(opts ...LoggerOption) *Logger {
+	options := loggerOptions{
+		level:       slog.LevelInfo,
+		format:      LogFormatJSON,
+		language:    LogLanguageRU,
+		output:      os.Stdout,
+		clearFields: DefaultClearFields,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	handlerOptions := &slog.HandlerOptions{
+		Level:       options.level,
+		ReplaceAttr: replaceLogAttr,
 	}
 
-	for k, v := range fields {
-		entry[k] = v
+	var handler slog.Handler
+	if options.format == LogFormatText {
+		handler = slog.NewTextHandler(options.output, handlerOptions)
+	} else {
+		handler = slog.NewJSONHandler(options.output, handlerOptions)
+	}
+
+	clear := make(map[string]bool, len(options.clearFields))
+	for _, key := range options.clearFields {
+		clear[key] = true
+	}
+	var keys map[string]string
+	if options.language == LogLanguageRU {
+		keys = russianLogKeys
 	}
+	handler = &redactHandler{Handler: handler, clear: clear, keys: keys}
 
-	data, _ := json.Marshal(entry)
-	l.logger.Println(string(data))
+	return &Logger{Logger: slog.New(&contextHandler{Handler: handler})}
+}
+
+// replaceLogAttr приводит служебные поля к прежнему виду: ts в UTC, уровень
+// строчными буквами.
+func replaceLogAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.TimeKey:
+			return slog.String(LogKeyTime, a.Value.Time().UTC().Format(time.RFC3339Nano))
+		case slog.LevelKey:
+			return slog.String(LogKeyLevel, strings.ToLower(a.Value.String()))
+		}
+	}
+	return a
+}
+
+// contextHandler дополняет запись идентификаторами запроса и трассировки из контекста.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if requestID, ok := ctx.Value(requestIDKey).(string); ok && requestID != "" {
+			record.AddAttrs(slog.String(LogKeyRequestID, requestID))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			record.AddAttrs(slog.String(LogKeyTraceID, sc.TraceID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// redactHandler маскирует телефоны и ФИО во всех полях, кроме разрешенных,
+// и в тексте сообщения. Ошибки и прочие значения приводятся к строке до
+// маскирования, поэтому обернутые ошибки pgx и значения паник тоже скрываются.
+// Он же переводит ключи: в отличие от ReplaceAttr, он видит и ключи групп.
+type redactHandler struct {
+	slog.Handler
+	clear map[string]bool
+	keys  map[string]string
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, redact.Text(ctx, record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(ctx, a))
+		return true
+	})
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(context.Background(), a)
+	}
+	return &redactHandler{Handler: h.Handler.WithAttrs(redacted), clear: h.clear, keys: h.keys}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{Handler: h.Handler.WithGroup(name), clear: h.clear, keys: h.keys}
+}
+
+func (h *redactHandler) redactAttr(ctx context.Context, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	clear := h.clear[a.Key]
+	if key, ok := h.keys[a.Key]; ok {
+		a.Key = key
+	}
+	if clear {
+		return a
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(redact.Text(ctx, a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = h.redactAttr(ctx, ga)
+		}
+		a.Value = slog.GroupValue(redacted...)
+	case slog.KindAny:
+		var text string
+		switch v := a.Value.Any().(type) {
+		case error:
+			text = v.Error()
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			text = fmt.Sprintf("%+v", v)
+		}
+		a.Value = slog.StringValue(redact.Text(ctx, text))
+	}
+	return a
 }
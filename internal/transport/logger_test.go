@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_FieldLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		wantKey  string
+	}{
+		{LogLanguageRU, "задержка_мс"},
+		{LogLanguageEN, "latency_ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(WithLogOutput(&buf), WithFieldLanguage(tt.language))
+
+			logger.Info("событие", slog.Int64(LogKeyLatencyMs, 42), slog.String("custom", "x"))
+
+			entries := decodeLogLines(t, &buf)
+			require.Len(t, entries, 1)
+			assert.Equal(t, "info", entries[0]["level"])
+			assert.Equal(t, "событие", entries[0]["msg"])
+			assert.Contains(t, entries[0], "ts")
+			assert.Equal(t, float64(42), entries[0][tt.wantKey])
+			assert.Equal(t, "x", entries[0]["custom"])
+		})
+	}
+}
+
+func TestLogger_LevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogOutput(&buf), WithLogLevel(slog.LevelWarn))
+
+	logger.Debug("отладка")
+	logger.Info("инфо")
+	logger.Warn("предупреждение")
+	logger.Error("ошибка")
+
+	entries := decodeLogLines(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "error", entries[1]["level"])
+}
+
+func TestLogger_TextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogOutput(&buf), WithLogFormat(LogFormatText), WithFieldLanguage(LogLanguageEN))
+
+	logger.Info("событие", slog.Int(LogKeyStatus, 200))
+
+	line := buf.String()
+	assert.Contains(t, line, "level=info")
+	assert.Contains(t, line, "msg=событие")
+	assert.Contains(t, line, "status=200")
+}
+
+func TestLogger_ContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogOutput(&buf), WithFieldLanguage(LogLanguageEN))
+
+	ctx := context.WithValue(context.Background(), requestIDKey, "req-1")
+	logger.InfoContext(ctx, "событие")
+	logger.With(slog.String("component", "test")).ErrorContext(ctx, "ошибка")
+	logger.Info("без_контекста")
+
+	entries := decodeLogLines(t, &buf)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "req-1", entries[0][LogKeyRequestID])
+	assert.Equal(t, "req-1", entries[1][LogKeyRequestID])
+	assert.Equal(t, "test", entries[1]["component"])
+	assert.NotContains(t, entries[2], LogKeyRequestID)
+}
+
+func TestLoggingMiddleware_LogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewHandler(nil, NewLogger(WithLogOutput(&buf)))
+	handler := h.requestIDMiddleware(h.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/employees", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := decodeLogLines(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "http_запрос", entries[0]["msg"])
+	assert.Equal(t, "req-42", entries[0]["ид_запроса"])
+	assert.Equal(t, "POST", entries[0]["метод"])
+	assert.Equal(t, float64(http.StatusCreated), entries[0]["статус"])
+}
+
+func TestLoggingMiddleware_LogsAbortedRequest(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewHandler(nil, NewLogger(WithLogOutput(&buf), WithFieldLanguage(LogLanguageEN)))
+	handler := h.loggingMiddleware(h.recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setRoute(r, "/v1/employees:export")
+		w.Write([]byte("id,fullName\n"))
+		panic(http.ErrAbortHandler)
+	})))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/employees:export", nil))
+	})
+
+	entries := decodeLogLines(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, float64(http.StatusOK), entries[0][LogKeyStatus])
+	assert.Equal(t, true, entries[0][LogKeyAborted])
+}
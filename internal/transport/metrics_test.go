@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employees-api/internal/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	h := NewHandler(nil, NewLogger(), WithMetrics(metrics.New()))
+	routes := h.Routes()
+
+	requests := []struct {
+		method string
+		target string
+		status int
+	}{
+		{http.MethodGet, "/v1/unknown/path", http.StatusNotFound},
+		{http.MethodPost, "/v1/employees/c91dd64b-773e-406b-873f-37cc13fa56d5", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/v1/employees:export?format=pdf", http.StatusUnprocessableEntity},
+	}
+	for _, req := range requests {
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, httptest.NewRequest(req.method, req.target, nil))
+		require.Equal(t, req.status, rec.Code, req.target)
+	}
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	text := string(body)
+
+	assert.Contains(t, text, `employees_api_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, text, `employees_api_http_requests_total{method="POST",route="/v1/employees/{id}",status="405"} 1`)
+	assert.Contains(t, text, `employees_api_http_requests_total{method="GET",route="/v1/employees:export",status="422"} 1`)
+	assert.Contains(t, text, `employees_api_validation_failures_total{field="format"} 1`)
+	assert.Contains(t, text, `employees_api_http_request_duration_seconds_bucket{method="GET",route="unmatched",status="404",le="+Inf"} 1`)
+	assert.NotContains(t, text, "c91dd64b", "сырые пути не должны попадать в метки")
+}
@@ -2,78 +2,196 @@ package transport
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
-	"employees-api/internal/repository"
+	"employees-api/internal/database"
+	"employees-api/internal/i18n"
+	"employees-api/internal/redact"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
 
-const requestIDKey contextKey = "requestID"
+const tracerName = "employees-api/internal/transport"
+
+const (
+	requestIDKey contextKey = "requestID"
+	routeKey     contextKey = "route"
+)
+
+// unmatchedRoute - метка маршрута для запросов, не попавших ни в один обработчик.
+// Сырые пути в метки не попадают, иначе число временных рядов не ограничено.
+const unmatchedRoute = "unmatched"
+
+// setRoute уточняет шаблон маршрута для метрик, когда один обработчик mux
+// обслуживает несколько маршрутов, например /v1/employees/{id} и /v1/employees/{id}:restore.
+func setRoute(r *http.Request, route string) {
+	if ptr, ok := r.Context().Value(routeKey).(*string); ok {
+		*ptr = route
+	}
+}
+
+// routeMiddleware записывает шаблон маршрута из mux; обработчики поддеревьев
+// уточняют его через setRoute.
+func (h *Handler) routeMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			setRoute(r, pattern)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// languageMiddleware выбирает язык сообщений об ошибках по Accept-Language.
+// Язык ответа сообщается в Content-Language.
+func (h *Handler) languageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+
+		w.Header().Set("Content-Language", string(lang))
+		w.Header().Add("Vary", "Accept-Language")
+
+		next.ServeHTTP(w, r.WithContext(i18n.WithLanguage(r.Context(), lang)))
+	})
+}
 
 func (h *Handler) requestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestID := r.Header.Get("X-Request-ID")
 		if requestID == "" {
-			requestID = uuid.New().String()
+			// Без заголовка ид запроса совпадает с ид трассировки, чтобы по строке
+			// лога можно было сразу найти трассировку.
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				requestID = sc.TraceID().String()
+			} else {
+				requestID = uuid.New().String()
+			}
 		}
 
-		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		ctx := ContextWithRequestID(r.Context(), requestID)
 		w.Header().Set("X-Request-ID", requestID)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// ContextWithRequestID кладет ид запроса в контекст, откуда его берет Logger.
+// Нужен транспортам без Handler, например gRPC.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// tracingMiddleware продолжает трассировку из W3C traceparent или начинает новую
+// и создает серверный спан запроса. Маршрут и статус дописывает loggingMiddleware.
+func (h *Handler) tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func (h *Handler) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		ctx, dbTimePtr := repository.WithDBTime(r.Context())
+		ctx, queryStats := database.WithQueryStats(r.Context())
+		ctx = redact.NewContext(ctx)
+		route := unmatchedRoute
+		ctx = context.WithValue(ctx, routeKey, &route)
 
 		lrw := &loggingResponseWriter{
 			ResponseWriter: w,
 			statusCode:     http.StatusOK,
+			queryStats:     queryStats,
 		}
 
-		next.ServeHTTP(lrw, r.WithContext(ctx))
-
-		duration := time.Since(start)
-		requestID, _ := r.Context().Value(requestIDKey).(string)
-
-		logData := map[string]interface{}{
-			"ид_запроса":  requestID,
-			"метод":       r.Method,
-			"путь":        r.URL.Path,
-			"статус":      lrw.statusCode,
-			"задержка_мс": duration.Milliseconds(),
-			"адрес":       r.RemoteAddr,
-		}
-
-		if dbTimePtr != nil && *dbTimePtr > 0 {
-			logData["время_бд_мс"] = *dbTimePtr
-		}
+		// Обработчик может оборвать ответ через panic(http.ErrAbortHandler),
+		// например при ошибке посреди выгрузки. Такой запрос тоже попадает в
+		// лог, метрики и трассировку, а паника уходит дальше в net/http.
+		defer func() {
+			p := recover()
+			h.observeRequest(ctx, r, lrw, route, time.Since(start), p != nil)
+			if p != nil {
+				panic(p)
+			}
+		}()
 
-		h.logger.Info("http_запрос", logData)
+		next.ServeHTTP(lrw, r.WithContext(ctx))
 	})
 }
 
+func (h *Handler) observeRequest(ctx context.Context, r *http.Request, lrw *loggingResponseWriter, route string, duration time.Duration, aborted bool) {
+	attrs := []slog.Attr{
+		slog.String(LogKeyMethod, r.Method),
+		slog.String(LogKeyPath, r.URL.Path),
+		slog.Int(LogKeyStatus, lrw.statusCode),
+		slog.Int64(LogKeyLatencyMs, duration.Milliseconds()),
+		slog.String(LogKeyRemoteAddr, r.RemoteAddr),
+	}
+	if aborted {
+		attrs = append(attrs, slog.Bool(LogKeyAborted, true))
+	}
+
+	if stats := lrw.queryStats.Snapshot(); stats.Count > 0 {
+		attrs = append(attrs,
+			slog.Float64(LogKeyDBTimeMs, durationMs(stats.Total)),
+			slog.Int(LogKeyDBQueries, stats.Count),
+			slog.String(LogKeySlowestQuery, stats.SlowestName),
+			slog.Float64(LogKeySlowestQueryMs, durationMs(stats.Slowest)),
+		)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetName(r.Method + " " + route)
+	span.SetAttributes(
+		semconv.HTTPRoute(route),
+		semconv.HTTPResponseStatusCode(lrw.statusCode),
+	)
+	switch {
+	case aborted:
+		span.SetStatus(codes.Error, "ответ прерван")
+	case lrw.statusCode >= http.StatusInternalServerError:
+		span.SetStatus(codes.Error, http.StatusText(lrw.statusCode))
+	}
+
+	h.logger.LogAttrs(ctx, slog.LevelInfo, "http_запрос", attrs...)
+	h.metrics.ObserveHTTPRequest(route, r.Method, lrw.statusCode, duration)
+}
+
 func (h *Handler) recoverMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				requestID, _ := r.Context().Value(requestIDKey).(string)
-				h.logger.Error("восстановление_паники", map[string]interface{}{
-					"ид_запроса": requestID,
-					"тип_ошибки": "паника",
-				})
-
-				respondError(w, ErrorResponse{
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				h.logger.ErrorContext(r.Context(), "восстановление_паники",
+					slog.String(LogKeyErrorType, "паника"),
+					slog.Any(LogKeyPanic, err),
+				)
+
+				h.respondError(w, r, ErrorResponse{
 					Code:    "internal_error",
-					Message: "Внутренняя ошибка сервера",
+					Message: i18n.T(r.Context(), i18n.MsgInternalError, nil),
 				}, http.StatusInternalServerError)
 			}
 		}()
@@ -84,10 +202,51 @@ func (h *Handler) recoverMiddleware(next http.Handler) http.Handler {
 
 type loggingResponseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode  int
+	wroteHeader bool
+	queryStats  *database.QueryStats
 }
 
 func (lrw *loggingResponseWriter) WriteHeader(code int) {
+	if !lrw.wroteHeader {
+		lrw.wroteHeader = true
+		setServerTiming(lrw.Header(), lrw.queryStats.Snapshot())
+	}
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+func (lrw *loggingResponseWriter) Write(p []byte) (int, error) {
+	if !lrw.wroteHeader {
+		lrw.WriteHeader(http.StatusOK)
+	}
+	return lrw.ResponseWriter.Write(p)
+}
+
+// Unwrap дает http.ResponseController доступ к Flush и дедлайнам исходного writer.
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
+
+// setServerTiming добавляет время БД в заголовок Server-Timing. Заголовок
+// отправляется вместе со статусом, поэтому учитывает запросы, выполненные до
+// начала ответа; для потоковых ответов полное время есть только в логе.
+func setServerTiming(header http.Header, stats database.QueryStatsSnapshot) {
+	if stats.Count == 0 {
+		return
+	}
+	header.Add("Server-Timing", fmt.Sprintf(`db;dur=%s;desc="%d queries"`,
+		formatMs(stats.Total), stats.Count))
+	header.Add("Server-Timing", fmt.Sprintf(`db-slowest;dur=%s;desc=%q`,
+		formatMs(stats.Slowest), stats.SlowestName))
+}
+
+// durationMs переводит длительность в миллисекунды с точностью до микросекунды,
+// чтобы быстрые запросы не округлялись до нуля.
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func formatMs(d time.Duration) string {
+	return strconv.FormatFloat(durationMs(d), 'f', -1, 64)
+}
@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"employees-api/internal/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingMiddleware_ServerTiming(t *testing.T) {
+	tracer := database.NewQueryTracer(nil)
+	h := NewHandler(nil, NewLogger())
+
+	handler := h.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 2; i++ {
+			ctx := tracer.TraceQueryStart(r.Context(), nil, pgx.TraceQueryStartData{
+				SQL: "-- name: employees.get_by_id\nSELECT 1",
+			})
+			time.Sleep(time.Millisecond)
+			tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/employees", nil))
+
+	timing := rec.Header().Values("Server-Timing")
+	if assert.Len(t, timing, 2) {
+		assert.Regexp(t, `^db;dur=\d+(\.\d+)?;desc="2 queries"$`, timing[0])
+		assert.Regexp(t, `^db-slowest;dur=\d+(\.\d+)?;desc="employees.get_by_id"$`, timing[1])
+	}
+}
+
+func TestLoggingMiddleware_NoServerTimingWithoutQueries(t *testing.T) {
+	h := NewHandler(nil, NewLogger())
+	handler := h.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/healthz", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Values("Server-Timing"))
+}
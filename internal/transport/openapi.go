@@ -0,0 +1,25 @@
+package transport
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec - контракт API в формате OpenAPI 3.1. Ограничения полей
+// совпадают с валидаторами service, ответы обработчиков сверяются со схемами
+// в тестах.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func (h *Handler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.respondMethodNotAllowed(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPISpec)
+}
@@ -0,0 +1,365 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"employees-api/internal/auth/authtest"
+	"employees-api/internal/domain"
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIDoc проверяет ответы обработчиков по схемам из openapi.json.
+type openAPIDoc struct {
+	doc      map[string]interface{}
+	compiler *jsonschema.Compiler
+}
+
+func loadOpenAPI(t *testing.T) *openAPIDoc {
+	t.Helper()
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(openAPISpec, &doc))
+	require.Equal(t, "3.1.0", doc["openapi"])
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	require.NoError(t, compiler.AddResource("openapi.json", bytes.NewReader(openAPISpec)))
+	return &openAPIDoc{doc: doc, compiler: compiler}
+}
+
+// lookup возвращает значение по JSON pointer, переходя по $ref.
+func (d *openAPIDoc) lookup(t *testing.T, pointer string) (map[string]interface{}, string) {
+	t.Helper()
+
+	var node interface{} = d.doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		obj, ok := node.(map[string]interface{})
+		require.True(t, ok, "нет %s в спецификации", pointer)
+		node, ok = obj[token]
+		require.True(t, ok, "нет %s в спецификации", pointer)
+	}
+
+	obj, ok := node.(map[string]interface{})
+	require.True(t, ok, "%s не объект", pointer)
+	if ref, ok := obj["$ref"].(string); ok {
+		return d.lookup(t, strings.TrimPrefix(ref, "#"))
+	}
+	return obj, pointer
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// validateResponse проверяет, что статус и Content-Type ответа описаны для
+// операции, а JSON тело соответствует схеме.
+func (d *openAPIDoc) validateResponse(t *testing.T, method, path string, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	operation := "/paths/" + escapePointer(path) + "/" + strings.ToLower(method)
+	response, pointer := d.lookup(t, operation+"/responses/"+strconv.Itoa(rec.Code))
+
+	content, ok := response["content"].(map[string]interface{})
+	if !ok {
+		assert.Empty(t, rec.Body.Bytes(), "%s %s %d: тело не описано", method, path, rec.Code)
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	require.NoError(t, err)
+	if _, ok := content[mediaType]; !ok {
+		t.Fatalf("%s %s %d: Content-Type %s не описан", method, path, rec.Code, mediaType)
+	}
+	if mediaType != "application/json" && mediaType != "application/problem+json" {
+		return
+	}
+
+	_, schemaPointer := d.lookup(t, pointer+"/content/"+escapePointer(mediaType)+"/schema")
+	schema, err := d.compiler.Compile("openapi.json#" + schemaPointer)
+	require.NoError(t, err)
+
+	var body interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.NoError(t, schema.Validate(body), "%s %s %d: %s", method, path, rec.Code, rec.Body.String())
+}
+
+type stubImports struct {
+	job *domain.ImportJob
+}
+
+func (s *stubImports) StartImport(ctx context.Context, req domain.ImportRequest) (*domain.ImportJob, error) {
+	return s.job, nil
+}
+
+func (s *stubImports) GetImportJob(ctx context.Context, id uuid.UUID) (*domain.ImportJob, error) {
+	if id != s.job.ID {
+		return nil, repository.ErrNotFound
+	}
+	return s.job, nil
+}
+
+func (s *stubImports) GetImportReport(ctx context.Context, id uuid.UUID) ([]domain.ImportRowError, error) {
+	if _, err := s.GetImportJob(ctx, id); err != nil {
+		return nil, err
+	}
+	return []domain.ImportRowError{{Row: 3, Field: "fullName", Code: "min_length", Params: map[string]interface{}{"min": 2}}}, nil
+}
+
+func TestOpenAPI_ResponsesMatchSchema(t *testing.T) {
+	spec := loadOpenAPI(t)
+
+	now := time.Now().UTC()
+	imports := &stubImports{job: &domain.ImportJob{
+		ID:         uuid.New(),
+		Status:     domain.ImportStatusCompleted,
+		Format:     domain.ImportFormatCSV,
+		Mapping:    domain.ImportColumnMapping{FullName: "fullName", Phone: "phone", City: "city"},
+		TotalRows:  3,
+		ValidRows:  2,
+		FailedRows: 1,
+		CreatedAt:  now,
+		StartedAt:  &now,
+		FinishedAt: &now,
+	}}
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	keys := service.NewAPIKeyService(repository.NewMemoryAPIKeyStore())
+	routes := NewHandler(svc, NewLogger(), WithImports(imports, 1<<20), WithAPIKeys(keys), WithGraphQL(DefaultGraphQLMaxCost)).Routes()
+
+	client, err := keys.CreateAPIKey(context.Background(), domain.CreateAPIKeyRequest{Name: "openapi", Scopes: []string{"employees.*"}})
+	require.NoError(t, err)
+
+	// do выполняет запрос и сверяет ответ со спецификацией; template - путь
+	// операции в спецификации.
+	do := func(method, template, path, body string, headers ...string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", client.Key)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		spec.validateResponse(t, method, template, rec)
+		return rec
+	}
+	expect := func(rec *httptest.ResponseRecorder, status int) {
+		t.Helper()
+		require.Equal(t, status, rec.Code, rec.Body.String())
+	}
+
+	const (
+		employees   = "/v1/employees"
+		employee    = "/v1/employees/{id}"
+		valid       = `{"fullName": "Иван Иванов", "phone": "+77010000001", "city": "Алматы"}`
+		invalid     = `{"fullName": "И", "phone": "8701", "city": ""}`
+		problemJSON = "application/problem+json"
+	)
+
+	expect(do(http.MethodGet, "/v1/openapi.json", "/v1/openapi.json", ""), http.StatusOK)
+	expect(do(http.MethodGet, "/v1/healthz", "/v1/healthz", ""), http.StatusOK)
+
+	rec := do(http.MethodPost, employees, employees, valid)
+	expect(rec, http.StatusCreated)
+	var created domain.Employee
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+	etag := rec.Header().Get("ETag")
+	path := "/v1/employees/" + created.ID.String()
+
+	expect(do(http.MethodPost, employees, employees, valid), http.StatusConflict)
+	expect(do(http.MethodPost, employees, employees, invalid), http.StatusUnprocessableEntity)
+	expect(do(http.MethodPost, employees, employees, invalid, "Accept", problemJSON), http.StatusUnprocessableEntity)
+	expect(do(http.MethodPost, employees, employees, `{"fullName":`), http.StatusBadRequest)
+
+	expect(do(http.MethodGet, employee, path, ""), http.StatusOK)
+	expect(do(http.MethodGet, employee, path, "", "If-None-Match", etag), http.StatusNotModified)
+	expect(do(http.MethodGet, employee, "/v1/employees/42", ""), http.StatusBadRequest)
+	expect(do(http.MethodGet, employee, "/v1/employees/"+uuid.NewString(), "", "Accept", problemJSON), http.StatusNotFound)
+
+	expect(do(http.MethodPut, employee, path, valid), http.StatusPreconditionRequired)
+	expect(do(http.MethodPut, employee, path, valid, "If-Match", `"999"`), http.StatusPreconditionFailed)
+	rec = do(http.MethodPut, employee, path, `{"fullName": "Иван Петров", "phone": "+77010000001", "city": "Алматы"}`, "If-Match", etag)
+	expect(rec, http.StatusOK)
+	etag = rec.Header().Get("ETag")
+
+	expect(do(http.MethodPatch, employee, path, `{"city": "Астана"}`, "Content-Type", "text/plain", "If-Match", etag), http.StatusUnsupportedMediaType)
+	expect(do(http.MethodPatch, employee, path, `{"city": null}`, "Content-Type", "application/merge-patch+json", "If-Match", etag), http.StatusUnprocessableEntity)
+	expect(do(http.MethodPatch, employee, path, `{"city": "Астана"}`, "Content-Type", "application/merge-patch+json", "If-Match", etag), http.StatusOK)
+
+	expect(do(http.MethodGet, employees, employees+"?limit=1&sort=-createdAt", ""), http.StatusOK)
+	expect(do(http.MethodGet, employees, employees+"?limit=abc&phonePrefix=x", ""), http.StatusUnprocessableEntity)
+	expect(do(http.MethodGet, "/v1/employees/search", "/v1/employees/search?q=Иван", ""), http.StatusOK)
+	expect(do(http.MethodGet, "/v1/employees/search", "/v1/employees/search?q=И", ""), http.StatusUnprocessableEntity)
+	expect(do(http.MethodGet, "/v1/employees:export", "/v1/employees:export?format=ndjson", ""), http.StatusOK)
+	expect(do(http.MethodGet, "/v1/employees:export", "/v1/employees:export?format=pdf", ""), http.StatusUnprocessableEntity)
+
+	expect(do(http.MethodPost, "/v1/employees:batch", "/v1/employees:batch", `{"mode": "bestEffort", "items": [
+		{"fullName": "Анна Смирнова", "phone": "+77010000002", "city": "Алматы"},
+		{"fullName": "Анна Смирнова", "phone": "+77010000002", "city": "Алматы"},
+		{"fullName": "А", "phone": "+77010000003", "city": "Алматы"}
+	]}`), http.StatusMultiStatus)
+	expect(do(http.MethodPost, "/v1/employees:batch", "/v1/employees:batch", `{"items": []}`), http.StatusUnprocessableEntity)
+
+	expect(do(http.MethodDelete, employee, path, "", "If-Match", "*"), http.StatusNoContent)
+	expect(do(http.MethodPost, "/v1/employees/{id}:restore", path+":restore", ""), http.StatusOK)
+	expect(do(http.MethodDelete, "/v1/admin/employees/{id}", "/v1/admin/employees/"+created.ID.String(), ""), http.StatusNoContent)
+	expect(do(http.MethodDelete, "/v1/admin/employees/{id}", "/v1/admin/employees/"+created.ID.String(), ""), http.StatusNotFound)
+
+	var form bytes.Buffer
+	var fw io.Writer
+	mw := multipart.NewWriter(&form)
+	fw, err = mw.CreateFormFile("file", "employees.csv")
+	require.NoError(t, err)
+	fw.Write([]byte("fullName,phone,city\nИван Иванов,+77010000009,Алматы\n"))
+	require.NoError(t, mw.Close())
+	expect(do(http.MethodPost, "/v1/imports", "/v1/imports", form.String(), "Content-Type", mw.FormDataContentType()), http.StatusAccepted)
+	expect(do(http.MethodPost, "/v1/imports", "/v1/imports", `{}`), http.StatusBadRequest)
+
+	jobPath := "/v1/imports/" + imports.job.ID.String()
+	expect(do(http.MethodGet, "/v1/imports/{id}", jobPath, ""), http.StatusOK)
+	expect(do(http.MethodGet, "/v1/imports/{id}", "/v1/imports/"+uuid.NewString(), ""), http.StatusNotFound)
+	expect(do(http.MethodGet, "/v1/imports/{id}/report", jobPath+"/report", ""), http.StatusOK)
+
+	const graphQL = "/v1/graphql"
+	expect(do(http.MethodPost, graphQL, graphQL, `{"query": "{ employees(limit: 1) { items { id fullName } nextCursor } }"}`), http.StatusOK)
+	expect(do(http.MethodPost, graphQL, graphQL, `{"query": "{ employees(limit: 100) { items { id } } a: employees(limit: 100) { items { id } } b: employees(limit: 100) { items { id } } c: employees(limit: 100) { items { id } } d: employees(limit: 100) { items { id } } e: employees(limit: 100) { items { id } } }"}`), http.StatusOK)
+	expect(do(http.MethodPost, graphQL, graphQL, `{"query": ""}`), http.StatusBadRequest)
+
+	const apiKeys = "/v1/admin/api-keys"
+	rec = do(http.MethodPost, apiKeys, apiKeys, `{"name": "payroll", "scopes": ["employees.read"]}`)
+	expect(rec, http.StatusCreated)
+	var issued domain.IssuedAPIKey
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&issued))
+	keyPath := apiKeys + "/" + issued.ID.String()
+
+	expect(do(http.MethodPost, apiKeys, apiKeys, `{"name": "", "scopes": ["payroll.read"]}`), http.StatusUnprocessableEntity)
+	expect(do(http.MethodGet, apiKeys, apiKeys, ""), http.StatusOK)
+	expect(do(http.MethodPost, "/v1/admin/api-keys/{id}:rotate", keyPath+":rotate", ""), http.StatusOK)
+	expect(do(http.MethodDelete, "/v1/admin/api-keys/{id}", keyPath, ""), http.StatusNoContent)
+	expect(do(http.MethodPost, "/v1/admin/api-keys/{id}:rotate", keyPath+":rotate", ""), http.StatusNotFound)
+}
+
+func TestOpenAPI_AuthAndRateLimitResponses(t *testing.T) {
+	spec := loadOpenAPI(t)
+
+	server := authtest.NewServer(t)
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	rules := ratelimit.NewRules(ratelimit.Limit{Requests: 1, Period: time.Minute, Burst: 1})
+	routes := NewHandler(svc, NewLogger(),
+		WithAuthentication(server.Authenticator(), "/v1/healthz"),
+		WithRateLimit(ratelimit.NewMemoryStore(), rules),
+	).Routes()
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/employees", nil))
+		spec.validateResponse(t, http.MethodGet, "/v1/employees", rec)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get().Code)
+
+	routes = NewHandler(svc, NewLogger(), WithRateLimit(ratelimit.NewMemoryStore(), rules)).Routes()
+	assert.Equal(t, http.StatusOK, get().Code)
+	assert.Equal(t, http.StatusTooManyRequests, get().Code)
+}
+
+// Ограничения полей в спецификации совпадают с валидаторами service.
+func TestOpenAPI_ValidationConstraints(t *testing.T) {
+	spec := loadOpenAPI(t)
+
+	number := func(schema map[string]interface{}, key string) int {
+		t.Helper()
+		value, ok := schema[key].(float64)
+		require.True(t, ok, "нет %s", key)
+		return int(value)
+	}
+	checkLength := func(name string, schema map[string]interface{}, validate func(string) error, min, max int) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, min, number(schema, "minLength"))
+			assert.Equal(t, max, number(schema, "maxLength"))
+
+			assert.Error(t, validate(strings.Repeat("а", min-1)))
+			assert.NoError(t, validate(strings.Repeat("а", min)))
+			assert.NoError(t, validate(strings.Repeat("а", max)))
+			assert.Error(t, validate(strings.Repeat("а", max+1)))
+		})
+	}
+	checkRange := func(name string, schema map[string]interface{}, validate func(int) error, min, max int) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, min, number(schema, "minimum"))
+			assert.Equal(t, max, number(schema, "maximum"))
+
+			assert.Error(t, validate(min-1))
+			assert.NoError(t, validate(min))
+			assert.NoError(t, validate(max))
+			assert.Error(t, validate(max+1))
+		})
+	}
+
+	fullName, _ := spec.lookup(t, "/components/schemas/FullName")
+	city, _ := spec.lookup(t, "/components/schemas/City")
+	phone, _ := spec.lookup(t, "/components/schemas/Phone")
+
+	checkLength("fullName", fullName, service.ValidateFullName, service.MinFullNameLength, service.MaxFullNameLength)
+	checkLength("city", city, service.ValidateCity, service.MinCityLength, service.MaxCityLength)
+
+	t.Run("patterns", func(t *testing.T) {
+		assert.Equal(t, service.FullNamePattern, fullName["pattern"])
+		assert.Equal(t, service.PhonePattern, phone["pattern"])
+	})
+
+	params := func(path string) map[string]map[string]interface{} {
+		operation, _ := spec.lookup(t, "/paths/"+escapePointer(path)+"/get")
+		result := make(map[string]map[string]interface{})
+		for _, p := range operation["parameters"].([]interface{}) {
+			param := p.(map[string]interface{})
+			if ref, ok := param["$ref"].(string); ok {
+				param, _ = spec.lookup(t, strings.TrimPrefix(ref, "#"))
+			}
+			result[param["name"].(string)] = param["schema"].(map[string]interface{})
+		}
+		return result
+	}
+	list := params("/v1/employees")
+	search := params("/v1/employees/search")
+
+	checkRange("limit", list["limit"], service.ValidateListLimit, 1, service.MaxListLimit)
+	checkRange("search limit", search["limit"], service.ValidateListLimit, 1, service.MaxListLimit)
+	checkLength("q", search["q"], service.ValidateSearchQuery, service.MinFullNameLength, service.MaxFullNameLength)
+
+	t.Run("phonePrefix", func(t *testing.T) {
+		pattern := regexp.MustCompile(list["phonePrefix"]["pattern"].(string))
+		for _, prefix := range []string{"+7701", "7701", "+", "+7a", "+1234567890123456", "+0"} {
+			valid := service.ValidatePhonePrefix(service.NormalizePhonePrefix(prefix)) == nil
+			assert.Equal(t, valid, pattern.MatchString(prefix), prefix)
+		}
+	})
+
+	t.Run("batch items", func(t *testing.T) {
+		batch, _ := spec.lookup(t, "/components/schemas/BatchCreateEmployeesRequest/properties/items")
+		assert.Equal(t, service.MaxBatchSize, number(batch, "maxItems"))
+	})
+}
@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	ErrorFormatJSON    = "json"
+	ErrorFormatProblem = "problem"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemTypePrefix - префикс URI типа проблемы, к нему дописывается код
+// ошибки: urn:employees-api:problem:validation_error.
+const problemTypePrefix = "urn:employees-api:problem:"
+
+// WithErrorFormat выбирает формат ошибок по умолчанию: ErrorFormatJSON
+// ({code, message, details}) или ErrorFormatProblem (RFC 9457). Клиент может
+// запросить Problem Details заголовком "Accept: application/problem+json" при
+// любом значении.
+func WithErrorFormat(format string) HandlerOption {
+	return func(h *Handler) {
+		h.problemDetails = format == ErrorFormatProblem
+	}
+}
+
+// Problem - тело ошибки в формате RFC 9457. Code и Errors - расширения с
+// кодом ошибки и нарушенными правилами валидации полей.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// newProblem переводит ErrorResponse в Problem. instance - ид запроса, по
+// нему ошибку можно найти в логах.
+func newProblem(r *http.Request, resp ErrorResponse, status int) Problem {
+	requestID, _ := r.Context().Value(requestIDKey).(string)
+	return Problem{
+		Type:     problemTypePrefix + resp.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   resp.Message,
+		Instance: requestID,
+		Code:     resp.Code,
+		Errors:   resp.Errors,
+	}
+}
+
+// respondError отвечает ошибкой в формате, выбранном сервером или клиентом.
+// Все ответы об ошибках Handler проходят через него.
+func (h *Handler) respondError(w http.ResponseWriter, r *http.Request, resp ErrorResponse, status int) {
+	w.Header().Add("Vary", "Accept")
+
+	if !h.problemDetails && !acceptsProblem(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(newProblem(r, resp, status))
+}
+
+// acceptsProblem сообщает, перечислен ли application/problem+json в Accept с
+// ненулевым q.
+func acceptsProblem(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), problemContentType) {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			value, ok := strings.CutPrefix(strings.TrimSpace(param), "q=")
+			if !ok {
+				continue
+			}
+			if q, err := strconv.ParseFloat(value, 64); err != nil || q <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemDetails(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+
+	tests := []struct {
+		name        string
+		opts        []HandlerOption
+		accept      string
+		wantProblem bool
+	}{
+		{"по умолчанию", nil, "", false},
+		{"клиент запросил", nil, "application/problem+json", true},
+		{"клиент запросил среди прочих", nil, "application/json, application/problem+json;q=0.9", true},
+		{"клиент отказался", nil, "application/problem+json;q=0", false},
+		{"настройка сервера", []HandlerOption{WithErrorFormat(ErrorFormatProblem)}, "application/json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes := NewHandler(svc, NewLogger(), tt.opts...).Routes()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/employees",
+				strings.NewReader(`{"fullName":"И","phone":"+77010000001","city":"Алматы"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Request-ID", "req-1")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+			routes.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept")
+
+			if !tt.wantProblem {
+				assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+				var resp ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, "validation_error", resp.Code)
+				return
+			}
+
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+			var problem Problem
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+			assert.Equal(t, "urn:employees-api:problem:validation_error", problem.Type)
+			assert.Equal(t, "Unprocessable Entity", problem.Title)
+			assert.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+			assert.Equal(t, "Ошибка валидации", problem.Detail)
+			assert.Equal(t, "req-1", problem.Instance)
+			assert.Equal(t, "validation_error", problem.Code)
+			require.Len(t, problem.Errors, 1)
+			assert.Equal(t, "fullName", problem.Errors[0].Field)
+			assert.Equal(t, "min_length", problem.Errors[0].Code)
+		})
+	}
+}
+
+func TestProblemDetails_NotFound(t *testing.T) {
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger(), WithErrorFormat(ErrorFormatProblem)).Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/employees/00000000-0000-0000-0000-000000000001", nil)
+	req.Header.Set("Accept-Language", "en")
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	var problem Problem
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&problem))
+	assert.Equal(t, "urn:employees-api:problem:not_found", problem.Type)
+	assert.Equal(t, "Not Found", problem.Title)
+	assert.Equal(t, "Employee not found", problem.Detail)
+	assert.Equal(t, rec.Header().Get("X-Request-ID"), problem.Instance)
+	assert.Empty(t, problem.Errors)
+}
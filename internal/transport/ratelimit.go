@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"employees-api/internal/auth"
+	"employees-api/internal/i18n"
+	"employees-api/internal/ratelimit"
+)
+
+// rateLimitMiddleware ограничивает частоту запросов клиента по правилам
+// маршрутов. Стоит внутри authMiddleware, чтобы различать клиентов по API
+// ключу и subject токена; анонимные клиенты различаются по IP.
+func (h *Handler) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule := h.rateLimits.Match(r.Method, r.URL.Path)
+		if rule.Limit.Unlimited() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if h.takeRateLimit(w, r, rule.String()+"|"+h.rateLimitClient(r), rule.Limit, rule.String()) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// ipRateLimitMiddleware ограничивает запросы с одного адреса до
+// аутентификации. Стоит снаружи authMiddleware: запросы с неверным токеном
+// или API ключом тоже расходуют корзину адреса, и перебор ключей не доходит
+// до поиска ключа в БД. Маршруты без ограничения (off) не учитываются.
+func (h *Handler) ipRateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.rateLimits.Match(r.Method, r.URL.Path).Limit.Unlimited() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if h.takeRateLimit(w, r, "ip|"+clientIP(r, h.trustedProxies), h.ipRateLimit, "ip") {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// takeRateLimit берет токен из корзины key и выставляет заголовки RateLimit.
+// Если токена нет, отвечает 429 и возвращает false.
+func (h *Handler) takeRateLimit(w http.ResponseWriter, r *http.Request, key string, limit ratelimit.Limit, label string) bool {
+	result, err := h.rateLimitStore.Take(r.Context(), key, limit)
+	if err != nil {
+		// Недоступное хранилище лимитов не должно останавливать API.
+		h.logger.WarnContext(r.Context(), "ошибка_ограничения_частоты", slog.Any(LogKeyError, err))
+		return true
+	}
+
+	header := w.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+	if !result.Allowed {
+		h.metrics.ObserveRateLimited(label)
+		header.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		h.respondError(w, r, ErrorResponse{
+			Code:    "rate_limited",
+			Message: i18n.T(r.Context(), i18n.MsgRateLimited, nil),
+		}, http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// rateLimitClient возвращает ключ клиента: API ключ, subject токена или IP.
+func (h *Handler) rateLimitClient(r *http.Request) string {
+	if identity, ok := auth.IdentityFromContext(r.Context()); ok && identity != nil {
+		if identity.Scopes != nil {
+			return identity.Subject
+		}
+		return "sub:" + identity.Subject
+	}
+	return "ip:" + clientIP(r, h.trustedProxies)
+}
+
+// clientIP берет адрес клиента из X-Forwarded-For, только если запрос пришел
+// от доверенного прокси: справа налево пропускаются доверенные адреса, первый
+// недоверенный считается клиентом. Иначе клиент подделал бы заголовок и
+// получил новую корзину на каждый запрос.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil || !isTrusted(remote, trusted) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr
+		if !isTrusted(addr, trusted) {
+			break
+		}
+	}
+	return client.Unmap().String()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
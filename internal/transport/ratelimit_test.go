@@ -0,0 +1,194 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"employees-api/internal/auth/authtest"
+	"employees-api/internal/domain"
+	"employees-api/internal/ratelimit"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedRoutes(t *testing.T, store ratelimit.Store, opts ...HandlerOption) http.Handler {
+	t.Helper()
+	rule, err := ratelimit.ParseRule("POST /v1/employees=1/m:1")
+	require.NoError(t, err)
+	healthz, err := ratelimit.ParseRule("* /v1/healthz=off")
+	require.NoError(t, err)
+
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	rules := ratelimit.NewRules(ratelimit.Limit{Requests: 2, Period: time.Minute, Burst: 2}, rule, healthz)
+	opts = append(opts, WithRateLimit(store, rules, netip.MustParsePrefix("10.0.0.0/8")))
+	return NewHandler(svc, NewLogger(), opts...).Routes()
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	routes := newRateLimitedRoutes(t, ratelimit.NewMemoryStore())
+
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/employees", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("192.0.2.1:1234")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+
+	require.Equal(t, http.StatusOK, get("192.0.2.1:1234").Code)
+
+	rec = get("192.0.2.1:5678")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	var errResp ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&errResp))
+	assert.Equal(t, "rate_limited", errResp.Code)
+
+	assert.Equal(t, http.StatusOK, get("192.0.2.2:1234").Code, "у другого IP своя корзина")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/healthz", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec = httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"), "healthz без ограничения")
+}
+
+func TestRateLimitMiddleware_PerRouteAndMethod(t *testing.T) {
+	routes := newRateLimitedRoutes(t, ratelimit.NewMemoryStore())
+
+	create := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/employees", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, create(), "первый POST проходит до обработчика")
+	assert.Equal(t, http.StatusTooManyRequests, create())
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/employees", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "GET считается по своему правилу")
+}
+
+func TestRateLimitMiddleware_KeyedBySubject(t *testing.T) {
+	server := authtest.NewServer(t)
+	routes := newRateLimitedRoutes(t, ratelimit.NewMemoryStore(), WithAuthentication(server.Authenticator()))
+
+	get := func(subject string) int {
+		req := httptest.NewRequest(http.MethodGet, "/v1/employees", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("Authorization", "Bearer "+server.SignRS256(t, authtest.Claims(subject, nil)))
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("user-1"))
+	assert.Equal(t, http.StatusOK, get("user-1"))
+	assert.Equal(t, http.StatusTooManyRequests, get("user-1"))
+	assert.Equal(t, http.StatusOK, get("user-2"), "клиенты с одного IP различаются по subject")
+}
+
+// countingAPIKeyStore считает поиски API ключей в хранилище.
+type countingAPIKeyStore struct {
+	repository.APIKeyStore
+	lookups atomic.Int32
+}
+
+func (s *countingAPIKeyStore) GetByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	s.lookups.Add(1)
+	return s.APIKeyStore.GetByHash(ctx, hash)
+}
+
+func TestIPRateLimitMiddleware_LimitsFailedAuthentication(t *testing.T) {
+	store := &countingAPIKeyStore{APIKeyStore: repository.NewMemoryAPIKeyStore()}
+	routes := newRateLimitedRoutes(t, ratelimit.NewMemoryStore(),
+		WithAPIKeys(service.NewAPIKeyService(store)),
+		WithIPRateLimit(ratelimit.Limit{Requests: 3, Period: time.Minute, Burst: 3}),
+	)
+
+	get := func(remoteAddr, path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-API-Key", service.APIKeyPrefix+"перебор")
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, get("192.0.2.1:1234", "/v1/employees"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get("192.0.2.1:1234", "/v1/employees"))
+	assert.Equal(t, int32(3), store.lookups.Load(), "отклоненный по лимиту запрос не ищет ключ в БД")
+
+	assert.Equal(t, http.StatusUnauthorized, get("192.0.2.2:1234", "/v1/employees"), "у другого IP своя корзина")
+	assert.Equal(t, http.StatusUnauthorized, get("192.0.2.1:1234", "/v1/healthz"), "маршруты без ограничения не учитываются")
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("хранилище недоступно")
+}
+
+func TestRateLimitMiddleware_StoreFailureAllows(t *testing.T) {
+	routes := newRateLimitedRoutes(t, failingRateLimitStore{})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/employees", nil)
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"без прокси", "192.0.2.1:1234", nil, "192.0.2.1"},
+		{"заголовок от недоверенного адреса", "192.0.2.1:1234", []string{"203.0.113.7"}, "192.0.2.1"},
+		{"доверенный прокси", "10.0.0.2:1234", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"цепочка прокси", "10.0.0.2:1234", []string{"198.51.100.1, 203.0.113.7, 10.0.0.3"}, "203.0.113.7"},
+		{"несколько заголовков", "10.0.0.2:1234", []string{"198.51.100.1", "203.0.113.7"}, "203.0.113.7"},
+		{"мусор в заголовке", "10.0.0.2:1234", []string{"unknown"}, "10.0.0.2"},
+		{"только доверенные", "10.0.0.2:1234", []string{"10.0.0.5"}, "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			assert.Equal(t, tt.want, clientIP(req, trusted))
+		})
+	}
+}
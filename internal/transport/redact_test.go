@@ -0,0 +1,134 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employees-api/internal/domain"
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingCreateStore имитирует ошибку pgx, в текст которой попали значения строки.
+type failingCreateStore struct {
+	repository.EmployeeStore
+}
+
+func (s failingCreateStore) Create(ctx context.Context, req domain.CreateEmployeeRequest) (*domain.Employee, error) {
+	return nil, fmt.Errorf("ошибка создания сотрудника: %w", &pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "23514",
+		Message:  fmt.Sprintf("failing row contains (%s, %s, %s)", req.FullName, req.Phone, req.City),
+	})
+}
+
+func assertNoPII(t *testing.T, output string, values ...string) {
+	t.Helper()
+	require.NotEmpty(t, output)
+	for _, value := range values {
+		assert.NotContains(t, output, value)
+	}
+}
+
+func TestRedaction_WrappedPgxError(t *testing.T) {
+	var buf bytes.Buffer
+	svc := service.NewEmployeeService(failingCreateStore{repository.NewMemoryEmployeeStore()})
+	routes := NewHandler(svc, NewLogger(WithLogOutput(&buf), WithFieldLanguage(LogLanguageEN))).Routes()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/employees",
+		strings.NewReader(`{"fullName":"мария сидорова","phone":"+77017654321","city":"Алматы"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	assertNoPII(t, buf.String(), "мария", "сидорова", "77017654321", "7654321")
+	assertNoPII(t, rec.Body.String(), "мария", "77017654321")
+
+	entries := decodeLogLines(t, &buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "ошибка_создания_сотрудника", entries[0]["msg"])
+	assert.Contains(t, entries[0][LogKeyError], "failing row contains (м*** с***, +7701***4321, Алматы)")
+}
+
+func TestRedaction_PanicValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name:  "строка",
+			value: "не удалось обработать +79991234567 для Иван Иванов",
+		},
+		{
+			name:  "ошибка",
+			value: fmt.Errorf("обработка: %w", fmt.Errorf("телефон +79991234567, ФИО Иван Иванов")),
+		},
+		{
+			name:  "сотрудник",
+			value: &domain.Employee{FullName: "Иван Иванов", Phone: "+79991234567", City: "Алматы"},
+		},
+		{
+			name:  "произвольная структура",
+			value: struct{ Name, Phone string }{"Иван Иванов", "+79991234567"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := NewHandler(nil, NewLogger(WithLogOutput(&buf)))
+			handler := h.loggingMiddleware(h.recoverMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(tt.value)
+			})))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/employees", nil))
+
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assertNoPII(t, buf.String(), "Иван", "Иванов", "79991234567", "1234567")
+
+			entries := decodeLogLines(t, &buf)
+			require.Len(t, entries, 2)
+			assert.Equal(t, "восстановление_паники", entries[0]["msg"])
+			assert.Contains(t, entries[0], "паника")
+			assert.Equal(t, float64(http.StatusInternalServerError), entries[1]["статус"])
+		})
+	}
+}
+
+func TestRedaction_ClearFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogOutput(&buf), WithFieldLanguage(LogLanguageEN), WithClearFields("note"))
+
+	logger.Info("событие",
+		slog.String("note", "Иван Иванов +79991234567"),
+		slog.String(LogKeyPath, "Иван Иванов +79991234567"),
+		slog.Group("employee", slog.String("phone", "+79991234567")),
+	)
+
+	entries := decodeLogLines(t, &buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Иван Иванов +79991234567", entries[0]["note"])
+	assert.Equal(t, "И*** И*** +7999***4567", entries[0][LogKeyPath])
+	assert.Equal(t, map[string]interface{}{"phone": "+7999***4567"}, entries[0]["employee"])
+}
+
+func TestRedaction_LoggerWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(WithLogOutput(&buf))
+
+	logger.With(slog.String("employee", "Иван Иванов")).Info("событие", slog.String("phone", "+79991234567"))
+
+	assertNoPII(t, buf.String(), "Иван", "79991234567")
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employees-api/internal/repository"
+	"employees-api/internal/service"
+	"employees-api/internal/tracing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_PropagatesTraceparent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := tracing.NewProvider(recorder, 1)
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	svc := service.NewEmployeeService(repository.NewMemoryEmployeeStore())
+	routes := NewHandler(svc, NewLogger()).Routes()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/v1/employees",
+		strings.NewReader(`{"fullName":"Иван Иванов","phone":"+77010000001","city":"Алматы"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+
+	rec := httptest.NewRecorder()
+	routes.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, traceID, rec.Header().Get("X-Request-ID"))
+
+	spans := make(map[string]trace.SpanContext)
+	parents := make(map[string]trace.SpanContext)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span.SpanContext()
+		parents[span.Name()] = span.Parent()
+		assert.Equal(t, traceID, span.SpanContext().TraceID().String(), span.Name())
+	}
+
+	require.Contains(t, spans, "POST /v1/employees")
+	require.Contains(t, spans, "EmployeeService.CreateEmployee")
+	assert.True(t, parents["POST /v1/employees"].IsRemote())
+	assert.Equal(t, spans["POST /v1/employees"].SpanID(), parents["EmployeeService.CreateEmployee"].SpanID())
+}
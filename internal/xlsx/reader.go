@@ -0,0 +1,363 @@
+// Package xlsx читает и пишет простые таблицы Office Open XML без внешних зависимостей:
+// только первый лист, только строковые и числовые значения ячеек.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoWorksheet = errors.New("в файле нет листов")
+	ErrTooManyRows = errors.New("слишком много строк")
+	ErrTooLarge    = errors.New("файл слишком большой после распаковки")
+)
+
+// Ограничения на содержимое книги: сжатый файл в несколько килобайт может
+// распаковаться в гигабайты или ссылаться на ячейку в миллионном столбце.
+const (
+	// MaxColumns - число столбцов листа Excel, последний - XFD.
+	MaxColumns = 16384
+	// MaxUncompressedSize ограничивает суммарный размер прочитанных частей книги.
+	MaxUncompressedSize = 256 << 20
+	// MaxSharedStrings ограничивает таблицу общих строк.
+	MaxSharedStrings = 1 << 20
+	// MaxCells ограничивает число ячеек результата вместе с пустыми ячейками,
+	// которыми дополняются строки.
+	MaxCells = 1 << 22
+)
+
+// ReadRows возвращает строки первого листа книги. Пустые ячейки внутри строки
+// заполняются пустыми строками, чтобы индекс совпадал с номером столбца.
+// maxRows ограничивает размер результата, так как сжатый лист может быть очень большим.
+func ReadRows(r io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("невалидный xlsx: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	budget := &sizeBudget{left: MaxUncompressedSize}
+
+	sheetPath, err := firstSheetPath(files, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		shared, err = readSharedStrings(f, budget)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrNoWorksheet
+	}
+	return readSheet(f, budget, shared, maxRows)
+}
+
+func firstSheetPath(files map[string]*zip.File, budget *sizeBudget) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	wb, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrNoWorksheet
+	}
+	if err := decodeFile(wb, budget, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", ErrNoWorksheet
+	}
+
+	if f, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := decodeFile(f, budget, &rels); err != nil {
+			return "", err
+		}
+		for _, rel := range rels.Relationships {
+			if rel.ID != workbook.Sheets[0].RID {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func readSharedStrings(f *zip.File, budget *sizeBudget) ([]string, error) {
+	rc, err := budget.open(f)
+	if err != nil {
+		return nil, fmt.Errorf("чтение sharedStrings: %w", err)
+	}
+	defer rc.Close()
+
+	var (
+		shared  []string
+		current strings.Builder
+		inItem  bool
+	)
+
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("чтение sharedStrings: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				inItem = true
+				current.Reset()
+			case "t":
+				if !inItem {
+					continue
+				}
+				var text string
+				if err := dec.DecodeElement(&text, &t); err != nil {
+					return nil, fmt.Errorf("чтение sharedStrings: %w", err)
+				}
+				current.WriteString(text)
+			case "rPh":
+				// Фонетические подсказки не являются частью значения.
+				if err := dec.Skip(); err != nil {
+					return nil, fmt.Errorf("чтение sharedStrings: %w", err)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "si" {
+				if len(shared) >= MaxSharedStrings {
+					return nil, fmt.Errorf("чтение sharedStrings: больше %d строк: %w", MaxSharedStrings, ErrTooLarge)
+				}
+				shared = append(shared, current.String())
+				inItem = false
+			}
+		}
+	}
+}
+
+type cell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+func readSheet(f *zip.File, budget *sizeBudget, shared []string, maxRows int) ([][]string, error) {
+	rc, err := budget.open(f)
+	if err != nil {
+		return nil, fmt.Errorf("чтение листа: %w", err)
+	}
+	defer rc.Close()
+
+	var (
+		rows  [][]string
+		row   []string
+		cells int
+	)
+
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("чтение листа: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = nil
+				if n, ok := attr(t, "r"); ok {
+					// Пропущенные пустые строки сохраняют нумерацию строк листа.
+					if idx, err := strconv.Atoi(n); err == nil {
+						if idx > maxRows {
+							return nil, ErrTooManyRows
+						}
+						for len(rows) < idx-1 {
+							rows = append(rows, nil)
+						}
+					}
+				}
+			case "c":
+				var c cell
+				if err := dec.DecodeElement(&c, &t); err != nil {
+					return nil, fmt.Errorf("чтение ячейки: %w", err)
+				}
+				col := len(row)
+				if c.Ref != "" {
+					idx, ok := columnIndex(c.Ref)
+					if !ok {
+						return nil, fmt.Errorf("ячейка %q: невалидная ссылка", c.Ref)
+					}
+					col = idx
+				}
+				if col >= MaxColumns {
+					return nil, fmt.Errorf("строка %d: больше %d столбцов", len(rows)+1, MaxColumns)
+				}
+				if col >= len(row) {
+					cells += col + 1 - len(row)
+					if cells > MaxCells {
+						return nil, fmt.Errorf("чтение листа: больше %d ячеек: %w", MaxCells, ErrTooLarge)
+					}
+					for len(row) <= col {
+						row = append(row, "")
+					}
+				}
+				value, err := cellValue(c, shared)
+				if err != nil {
+					return nil, err
+				}
+				row[col] = value
+			}
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				if len(rows) >= maxRows {
+					return nil, ErrTooManyRows
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+}
+
+func cellValue(c cell, shared []string) (string, error) {
+	switch c.Type {
+	case "s":
+		idx, err := strconv.Atoi(c.Value)
+		if err != nil || idx < 0 || idx >= len(shared) {
+			return "", fmt.Errorf("ячейка %s: невалидная ссылка на строку", c.Ref)
+		}
+		return shared[idx], nil
+	case "inlineStr":
+		if len(c.Inline.Runs) == 0 {
+			return c.Inline.Text, nil
+		}
+		var b strings.Builder
+		for _, run := range c.Inline.Runs {
+			b.WriteString(run.Text)
+		}
+		return b.String(), nil
+	default:
+		return c.Value, nil
+	}
+}
+
+// columnIndex переводит ссылку вида "AB12" в индекс столбца с нуля. Ссылка
+// без столбца или за пределами XFD невалидна.
+func columnIndex(ref string) (int, bool) {
+	idx := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		idx = idx*26 + int(ch-'A'+1)
+		if idx > MaxColumns {
+			return 0, false
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return idx - 1, true
+}
+
+func attr(el xml.StartElement, name string) (string, bool) {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func decodeFile(f *zip.File, budget *sizeBudget, v interface{}) error {
+	rc, err := budget.open(f)
+	if err != nil {
+		return fmt.Errorf("чтение %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("чтение %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// sizeBudget - сколько байт еще можно распаковать из книги. Заголовки zip
+// содержат размер частей, но им нельзя доверять, поэтому считаются
+// фактически прочитанные байты.
+type sizeBudget struct {
+	left int64
+}
+
+func (b *sizeBudget) open(f *zip.File) (io.ReadCloser, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &budgetReader{ReadCloser: rc, budget: b}, nil
+}
+
+type budgetReader struct {
+	io.ReadCloser
+	budget *sizeBudget
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	if r.budget.left <= 0 {
+		// Бюджет исчерпан ровно на конце части - это еще не превышение.
+		var probe [1]byte
+		if n, err := r.ReadCloser.Read(probe[:]); n == 0 {
+			return 0, err
+		}
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > r.budget.left {
+		p = p[:r.budget.left]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.budget.left -= int64(n)
+	return n, err
+}
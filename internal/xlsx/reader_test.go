@@ -0,0 +1,167 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildWorkbook(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestReadRows(t *testing.T) {
+	r := buildWorkbook(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+			xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Сотрудники" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId7" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>ФИО</t></si><si><r><t>Әлихан </t></r><r><t>Нұрғалиев</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="inlineStr"><is><t>Город</t></is></c></row>
+			<row r="3"><c r="A3" t="s"><v>1</v></c><c r="B3"><v>77011234567</v></c><c r="C3" t="str"><v>Алматы</v></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	rows, err := ReadRows(r, r.Size(), 100)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"ФИО", "", "Город"},
+		nil,
+		{"Әлихан Нұрғалиев", "77011234567", "Алматы"},
+	}, rows)
+}
+
+func TestReadRows_TooManyRows(t *testing.T) {
+	r := buildWorkbook(t, map[string]string{
+		"xl/workbook.xml":          `<workbook><sheets><sheet name="Лист1"/></sheets></workbook>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c><v>1</v></c></row><row><c><v>2</v></c></row></sheetData></worksheet>`,
+	})
+
+	_, err := ReadRows(r, r.Size(), 1)
+	assert.ErrorIs(t, err, ErrTooManyRows)
+}
+
+func TestReadRows_HostileCellReference(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+	}{
+		{"переполнение int", "ZZZZZZZZZZZZZZ1"},
+		{"столбец на 321M ячеек", "ZZZZZZ1"},
+		{"сразу за XFD", "XFE1"},
+		{"без столбца", "12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := buildWorkbook(t, map[string]string{
+				"xl/workbook.xml":          `<workbook><sheets><sheet name="Лист1"/></sheets></workbook>`,
+				"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="` + tt.ref + `"><v>1</v></c></row></sheetData></worksheet>`,
+			})
+
+			_, err := ReadRows(r, r.Size(), 10)
+			assert.ErrorContains(t, err, "невалидная ссылка")
+		})
+	}
+}
+
+func TestReadRows_TooManyCells(t *testing.T) {
+	// Каждая строка валидна, но вместе с пустыми ячейками до XFD их больше MaxCells.
+	var sheet strings.Builder
+	sheet.WriteString("<worksheet><sheetData>")
+	for i := 1; i <= MaxCells/MaxColumns+1; i++ {
+		fmt.Fprintf(&sheet, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, i, i)
+	}
+	sheet.WriteString("</sheetData></worksheet>")
+
+	r := buildWorkbook(t, map[string]string{
+		"xl/workbook.xml":          `<workbook><sheets><sheet name="Лист1"/></sheets></workbook>`,
+		"xl/worksheets/sheet1.xml": sheet.String(),
+	})
+
+	_, err := ReadRows(r, r.Size(), 1000)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestSizeBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{"меньше бюджета", "1234", nil},
+		{"ровно по бюджету", "12345", nil},
+		{"больше бюджета", "123456", ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := buildWorkbook(t, map[string]string{"part": tt.content})
+			zr, err := zip.NewReader(r, r.Size())
+			require.NoError(t, err)
+
+			rc, err := (&sizeBudget{left: 5}).open(zr.File[0])
+			require.NoError(t, err)
+			defer rc.Close()
+
+			data, err := io.ReadAll(rc)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(data))
+		})
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := map[string]int{"A1": 0, "Z9": 25, "AA10": 26, "AB2": 27, "XFD1": MaxColumns - 1}
+	for ref, want := range tests {
+		got, ok := columnIndex(ref)
+		assert.True(t, ok)
+		assert.Equal(t, want, got, ref)
+	}
+
+	for _, ref := range []string{"XFE1", "ZZZZZZZZZZZZZZ1", "1"} {
+		_, ok := columnIndex(ref)
+		assert.False(t, ok, ref)
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, "Сотрудники")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow([]string{"fullName", "phone"}))
+	require.NoError(t, w.WriteRow([]string{"Анна <Мария> & Co", "+77011234567"}))
+	require.NoError(t, w.Close())
+
+	rows, err := ReadRows(bytes.NewReader(buf.Bytes()), int64(buf.Len()), 10)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"fullName", "phone"},
+		{"Анна <Мария> & Co", "+77011234567"},
+	}, rows)
+}
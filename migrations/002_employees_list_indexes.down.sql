@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_employees_phone_pattern;
+DROP INDEX IF EXISTS idx_employees_created_at_id;
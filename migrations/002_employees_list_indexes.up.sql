@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_employees_created_at_id ON employees(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_employees_phone_pattern ON employees(phone text_pattern_ops);
@@ -0,0 +1,6 @@
+DELETE FROM employees WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_employees_phone;
+CREATE UNIQUE INDEX idx_employees_phone ON employees(phone);
+
+ALTER TABLE employees DROP COLUMN IF EXISTS deleted_at;
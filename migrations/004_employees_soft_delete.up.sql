@@ -0,0 +1,4 @@
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_employees_phone;
+CREATE UNIQUE INDEX idx_employees_phone ON employees(phone) WHERE deleted_at IS NULL;
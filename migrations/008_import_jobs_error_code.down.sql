@@ -0,0 +1,2 @@
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS error_params;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS error_code;
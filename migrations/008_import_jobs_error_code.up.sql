@@ -0,0 +1,2 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS error_code TEXT NOT NULL DEFAULT '';
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS error_params JSONB;
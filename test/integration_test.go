@@ -3,9 +3,13 @@ package test
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -15,7 +19,9 @@ import (
 	"employees-api/internal/repository"
 	"employees-api/internal/service"
 	"employees-api/internal/transport"
+	"employees-api/internal/xlsx"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -28,7 +34,8 @@ type testServer struct {
 	cleanup func()
 }
 
-func setupTestServer(t *testing.T) *testServer {
+// setupTestDB поднимает PostgreSQL в контейнере и применяет миграции.
+func setupTestDB(t *testing.T) (*pgxpool.Pool, func()) {
 	ctx := context.Background()
 
 	pgContainer, err := postgres.RunContainer(ctx,
@@ -52,15 +59,11 @@ func setupTestServer(t *testing.T) *testServer {
 	dsn := fmt.Sprintf("postgres://postgres:postgres@%s:%s/testdb?sslmode=disable", host, port.Port())
 
 	cfg := &config.Config{
-		Port:                "8888",
 		PostgresDSN:         dsn,
 		DBMaxConns:          10,
 		DBMinConns:          2,
 		DBMaxConnLifetime:   time.Hour,
 		DBHealthCheckPeriod: time.Minute,
-		ReadTimeout:         5 * time.Second,
-		WriteTimeout:        10 * time.Second,
-		RunMigrations:       true,
 	}
 
 	pool, err := database.NewPool(ctx, cfg)
@@ -69,13 +72,27 @@ func setupTestServer(t *testing.T) *testServer {
 	err = database.RunMigrations(ctx, pool, "../migrations")
 	require.NoError(t, err)
 
+	return pool, func() {
+		pool.Close()
+		pgContainer.Terminate(ctx)
+	}
+}
+
+func setupTestServer(t *testing.T) *testServer {
+	ctx := context.Background()
+	pool, closeDB := setupTestDB(t)
+
 	repo := repository.NewEmployeeRepository(pool)
 	svc := service.NewEmployeeService(repo)
 	logger := transport.NewLogger()
-	handler := transport.NewHandler(svc, logger)
+	imports := service.NewImportService(repo, repository.NewImportJobRepository(pool), time.Minute)
+	handler := transport.NewHandler(svc, logger,
+		transport.WithIdempotency(repository.NewIdempotencyRepository(pool), time.Hour),
+		transport.WithImports(imports, 1024*1024),
+	)
 
 	server := &http.Server{
-		Addr:    ":" + cfg.Port,
+		Addr:    ":8888",
 		Handler: handler.Routes(),
 	}
 
@@ -86,11 +103,11 @@ func setupTestServer(t *testing.T) *testServer {
 	time.Sleep(500 * time.Millisecond)
 
 	return &testServer{
-		baseURL: "http://localhost:" + cfg.Port,
+		baseURL: "http://localhost:8888",
 		cleanup: func() {
 			server.Shutdown(ctx)
-			pool.Close()
-			pgContainer.Terminate(ctx)
+			imports.Shutdown(ctx)
+			closeDB()
 		},
 	}
 }
@@ -224,3 +241,584 @@ func TestHealthCheck(t *testing.T) {
 	json.NewDecoder(resp.Body).Decode(&result)
 	assert.Equal(t, "ok", result["status"])
 }
+
+func TestListEmployees_Pagination(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	employees := []domain.CreateEmployeeRequest{
+		{FullName: "Анна Смирнова", Phone: "+77011111111", City: "Алматы"},
+		{FullName: "Болат Ахметов", Phone: "+77012222222", City: "Алматы"},
+		{FullName: "Виктор Ким", Phone: "+77013333333", City: "Алматы"},
+		{FullName: "John Smith", Phone: "+12025550100", City: "Астана"},
+	}
+	for _, reqBody := range employees {
+		body, _ := json.Marshal(reqBody)
+		resp, err := http.Post(srv.baseURL+"/v1/employees", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	query := url.Values{}
+	query.Set("city", "Алматы")
+	query.Set("phonePrefix", "+7701")
+	query.Set("limit", "2")
+
+	var seen []string
+	for {
+		resp, err := http.Get(srv.baseURL + "/v1/employees?" + query.Encode())
+		require.NoError(t, err)
+
+		var list domain.EmployeeList
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+		resp.Body.Close()
+
+		for _, emp := range list.Items {
+			assert.Equal(t, "Алматы", emp.City)
+			seen = append(seen, emp.Phone)
+		}
+
+		if list.NextCursor == nil {
+			break
+		}
+		query.Set("cursor", *list.NextCursor)
+	}
+
+	assert.Equal(t, []string{"+77011111111", "+77012222222", "+77013333333"}, seen)
+}
+
+func TestListEmployees_InvalidLimit(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	resp, err := http.Get(srv.baseURL + "/v1/employees?limit=1000")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestSearchEmployees_Fuzzy(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	employees := []domain.CreateEmployeeRequest{
+		{FullName: "Әлихан Нұрғалиев", Phone: "+77015555555", City: "Алматы"},
+		{FullName: "Иван Иванов", Phone: "+79993333333", City: "Москва"},
+		{FullName: "John Smith", Phone: "+12025550101", City: "Boston"},
+	}
+	for _, reqBody := range employees {
+		body, _ := json.Marshal(reqBody)
+		resp, err := http.Post(srv.baseURL+"/v1/employees", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "Иваноф", expected: "Иван Иванов"},
+		{query: "Нургалиев Алихан", expected: "Әлихан Нұрғалиев"},
+		{query: "Jon Smit", expected: "John Smith"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query := url.Values{}
+			query.Set("q", tt.query)
+			query.Set("threshold", "0.1")
+
+			resp, err := http.Get(srv.baseURL + "/v1/employees/search?" + query.Encode())
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var result domain.EmployeeSearchResult
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			require.NotEmpty(t, result.Items)
+			assert.Equal(t, tt.expected, result.Items[0].FullName)
+			assert.Greater(t, result.Items[0].Score, 0.0)
+		})
+	}
+}
+
+func createTestEmployee(t *testing.T, baseURL string, reqBody domain.CreateEmployeeRequest) domain.Employee {
+	t.Helper()
+
+	body, _ := json.Marshal(reqBody)
+	resp, err := http.Post(baseURL+"/v1/employees", "application/json", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var emp domain.Employee
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&emp))
+	return emp
+}
+
+func doJSON(t *testing.T, method, url, contentType, body string, headers ...string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestUpdateEmployee_PutAndPatch(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	created := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Иван Иванов",
+		Phone:    "+79994444444",
+		City:     "Москва",
+	})
+	other := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Петр Петров",
+		Phone:    "+79995555555",
+		City:     "Москва",
+	})
+	employeeURL := srv.baseURL + "/v1/employees/" + created.ID.String()
+
+	resp := doJSON(t, http.MethodPut, employeeURL, "application/json",
+		`{"fullName":"Иван Петрович Иванов","phone":"+79994444444","city":"Казань"}`, "If-Match", "*")
+	var updated domain.Employee
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+	resp.Body.Close()
+	assert.Equal(t, "Иван Петрович Иванов", updated.FullName)
+	assert.Equal(t, "Казань", updated.City)
+	assert.True(t, updated.UpdatedAt.After(created.UpdatedAt))
+
+	resp = doJSON(t, http.MethodPatch, employeeURL, "application/merge-patch+json", `{"city":" Алматы "}`,
+		"If-Match", resp.Header.Get("ETag"))
+	var patched domain.Employee
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&patched))
+	resp.Body.Close()
+	assert.Equal(t, "Иван Петрович Иванов", patched.FullName)
+	assert.Equal(t, "Алматы", patched.City)
+
+	resp = doJSON(t, http.MethodPatch, employeeURL, "application/merge-patch+json", `{"phone":"`+other.Phone+`"}`,
+		"If-Match", "*")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodPatch, employeeURL, "application/merge-patch+json", `{"city":null,"phone":"bad"}`,
+		"If-Match", "*")
+	var errResp transport.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Contains(t, errResp.Details, "city")
+
+	resp = doJSON(t, http.MethodPut, srv.baseURL+"/v1/employees/00000000-0000-0000-0000-000000000000", "application/json",
+		`{"fullName":"Иван Иванов","phone":"+79996666666","city":"Москва"}`, "If-Match", "*")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestUpdateEmployee_OptimisticConcurrency(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	created := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Айгерим Сейткали",
+		Phone:    "+77017777777",
+		City:     "Астана",
+	})
+	employeeURL := srv.baseURL + "/v1/employees/" + created.ID.String()
+
+	resp, err := http.Get(employeeURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req, _ := http.NewRequest(http.MethodGet, employeeURL, nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodPatch, employeeURL, "application/merge-patch+json", `{"city":"Алматы"}`)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionRequired, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodPatch, employeeURL, "application/merge-patch+json", `{"city":"Алматы"}`, "If-Match", etag)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+
+	resp = doJSON(t, http.MethodPatch, employeeURL, "application/merge-patch+json", `{"city":"Шымкент"}`, "If-Match", etag)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+}
+
+func TestDeleteEmployee_SoftDeleteRestorePurge(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	created := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Дина Оспанова",
+		Phone:    "+77018888888",
+		City:     "Караганда",
+	})
+	employeeURL := srv.baseURL + "/v1/employees/" + created.ID.String()
+
+	resp := doJSON(t, http.MethodDelete, employeeURL, "application/json", "")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionRequired, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodDelete, employeeURL, "application/json", "", "If-Match", `"1"`)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err := http.Get(employeeURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodPost, employeeURL+":restore", "application/json", "")
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodDelete, employeeURL, "application/json", "", "If-Match", "*")
+	resp.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	reassigned := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Ерлан Жумабаев",
+		Phone:    created.Phone,
+		City:     "Караганда",
+	})
+	assert.NotEqual(t, created.ID, reassigned.ID)
+
+	resp = doJSON(t, http.MethodPost, employeeURL+":restore", "application/json", "")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodDelete, srv.baseURL+"/v1/admin/employees/"+created.ID.String(), "application/json", "")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = doJSON(t, http.MethodPost, employeeURL+":restore", "application/json", "")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCreateEmployee_IdempotencyKey(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	body := `{"fullName":"Мадина Касымова","phone":"+77019999999","city":"Алматы"}`
+
+	first := doJSON(t, http.MethodPost, srv.baseURL+"/v1/employees", "application/json", body, "Idempotency-Key", "onboarding-42")
+	var created domain.Employee
+	require.NoError(t, json.NewDecoder(first.Body).Decode(&created))
+	first.Body.Close()
+	require.Equal(t, http.StatusCreated, first.StatusCode)
+
+	replay := doJSON(t, http.MethodPost, srv.baseURL+"/v1/employees", "application/json", body, "Idempotency-Key", "onboarding-42")
+	var replayed domain.Employee
+	require.NoError(t, json.NewDecoder(replay.Body).Decode(&replayed))
+	replay.Body.Close()
+	assert.Equal(t, http.StatusCreated, replay.StatusCode)
+	assert.Equal(t, first.Header.Get("Location"), replay.Header.Get("Location"))
+	assert.Equal(t, "true", replay.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, created.ID, replayed.ID)
+
+	reused := doJSON(t, http.MethodPost, srv.baseURL+"/v1/employees", "application/json",
+		`{"fullName":"Другой Человек","phone":"+77010000001","city":"Алматы"}`, "Idempotency-Key", "onboarding-42")
+	reused.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.StatusCode)
+
+	conflict := doJSON(t, http.MethodPost, srv.baseURL+"/v1/employees", "application/json", body, "Idempotency-Key", "onboarding-43")
+	conflict.Body.Close()
+	assert.Equal(t, http.StatusConflict, conflict.StatusCode)
+}
+
+func TestIdempotencyRepository_TakesOverStaleReservation(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := repository.NewIdempotencyRepository(pool)
+
+	_, reserved, err := repo.Reserve(ctx, "onboarding-42", "fp", time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	record, reserved, err := repo.Reserve(ctx, "onboarding-42", "fp", time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, reserved, "ключ занят незавершенным запросом")
+	assert.False(t, record.Completed())
+
+	time.Sleep(10 * time.Millisecond)
+	_, reserved, err = repo.Reserve(ctx, "onboarding-42", "fp", time.Hour, time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, reserved, "брошенный ключ занимается заново")
+
+	require.NoError(t, repo.Complete(ctx, "onboarding-42", http.StatusCreated, nil, []byte("{}")))
+	record, reserved, err = repo.Reserve(ctx, "onboarding-42", "fp", time.Hour, time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, reserved, "завершенный ключ не занимается до истечения TTL")
+	assert.Equal(t, http.StatusCreated, record.StatusCode)
+}
+
+func TestCreateEmployeesBatch(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	existing := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Тимур Абдрахманов",
+		Phone:    "+77020000000",
+		City:     "Астана",
+	})
+
+	items := `[
+		{"fullName":"Асель Нурланова","phone":"+77020000001","city":"Астана"},
+		{"fullName":"A","phone":"bad","city":"Астана"},
+		{"fullName":"Бауыржан Омаров","phone":"` + existing.Phone + `","city":"Астана"},
+		{"fullName":"Гульнара Сапарова","phone":"+77020000002","city":"Астана"}
+	]`
+
+	decode := func(resp *http.Response) transport.BatchCreateResponse {
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+		var result transport.BatchCreateResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result
+	}
+
+	atomic := decode(doJSON(t, http.MethodPost, srv.baseURL+"/v1/employees:batch", "application/json",
+		`{"mode":"atomic","items":`+items+`}`))
+	assert.Equal(t, 0, atomic.Created)
+	require.Len(t, atomic.Items, 4)
+	assert.Equal(t, http.StatusFailedDependency, atomic.Items[0].Status)
+	assert.Equal(t, http.StatusUnprocessableEntity, atomic.Items[1].Status)
+	assert.Equal(t, http.StatusFailedDependency, atomic.Items[2].Status)
+
+	bestEffort := decode(doJSON(t, http.MethodPost, srv.baseURL+"/v1/employees:batch", "application/json",
+		`{"mode":"bestEffort","items":`+items+`}`))
+	assert.Equal(t, 2, bestEffort.Created)
+	assert.Equal(t, 2, bestEffort.Failed)
+	assert.Equal(t, http.StatusCreated, bestEffort.Items[0].Status)
+	require.NotNil(t, bestEffort.Items[0].Employee)
+	assert.Equal(t, "Асель Нурланова", bestEffort.Items[0].Employee.FullName)
+	assert.Equal(t, http.StatusUnprocessableEntity, bestEffort.Items[1].Status)
+	assert.Equal(t, http.StatusConflict, bestEffort.Items[2].Status)
+	assert.Equal(t, "duplicate_phone", bestEffort.Items[2].Error.Code)
+	assert.Equal(t, http.StatusCreated, bestEffort.Items[3].Status)
+}
+
+func uploadImport(t *testing.T, baseURL, filename, content string, fields map[string]string) transport.ImportJobResponse {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, mw.WriteField(name, value))
+	}
+	fw, err := mw.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	resp, err := http.Post(baseURL+"/v1/imports", mw.FormDataContentType(), &body)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var job transport.ImportJobResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(baseURL + "/v1/imports/" + job.ID.String())
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		job = transport.ImportJobResponse{}
+		json.NewDecoder(resp.Body).Decode(&job)
+		return job.ImportJob != nil && job.Status != domain.ImportStatusPending && job.Status != domain.ImportStatusRunning
+	}, 10*time.Second, 100*time.Millisecond)
+
+	return job
+}
+
+func TestImportEmployees_CSV(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	existing := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Серик Беков",
+		Phone:    "+77030000000",
+		City:     "Атырау",
+	})
+
+	content := "ФИО;Телефон;Город\n" +
+		"Айдана Муратова;+77030000001;Атырау\n" +
+		"A;+77030000002;Атырау\n" +
+		"Нурлан Есенов;" + existing.Phone + ";Атырау\n" +
+		"Ольга Ли;+77030000001;Атырау\n"
+	mapping := `{"fullName":"ФИО","phone":"Телефон","city":"Город"}`
+
+	dryRun := uploadImport(t, srv.baseURL, "employees.csv", content, map[string]string{
+		"dryRun":  "true",
+		"mapping": mapping,
+	})
+	assert.Equal(t, domain.ImportStatusCompleted, dryRun.Status)
+	assert.Equal(t, 4, dryRun.TotalRows)
+	assert.Equal(t, 1, dryRun.ValidRows)
+	assert.Equal(t, 0, dryRun.CreatedRows)
+	assert.Equal(t, 3, dryRun.FailedRows)
+
+	resp, err := http.Get(srv.baseURL + "/v1/employees?city=" + url.QueryEscape("Атырау"))
+	require.NoError(t, err)
+	var list domain.EmployeeList
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	assert.Len(t, list.Items, 1)
+
+	job := uploadImport(t, srv.baseURL, "employees.csv", content, map[string]string{"mapping": mapping})
+	assert.Equal(t, domain.ImportStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.CreatedRows)
+	require.NotEmpty(t, job.ReportURL)
+
+	resp, err = http.Get(srv.baseURL + job.ReportURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	report, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, report, 4)
+	assert.Equal(t, []string{"row", "field", "message"}, report[0])
+	assert.Equal(t, "3", report[1][0])
+	assert.Equal(t, "fullName", report[1][1])
+	assert.Equal(t, "4", report[2][0])
+	assert.Equal(t, "5", report[3][0])
+}
+
+func TestExportEmployees(t *testing.T) {
+	srv := setupTestServer(t)
+	defer srv.cleanup()
+
+	first := createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Дана Серикова",
+		Phone:    "+77040000001",
+		City:     "Шымкент",
+	})
+	createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Ерлан Касымов",
+		Phone:    "+77040000002",
+		City:     "Шымкент",
+	})
+	createTestEmployee(t, srv.baseURL, domain.CreateEmployeeRequest{
+		FullName: "Игорь Пак",
+		Phone:    "+77040000003",
+		City:     "Тараз",
+	})
+
+	query := url.Values{}
+	query.Set("city", "Шымкент")
+
+	resp, err := http.Get(srv.baseURL + "/v1/employees:export?" + query.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), `attachment; filename="employees-`)
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	resp.Body.Close()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"id", "fullName", "phone", "city", "createdAt", "updatedAt"}, records[0])
+	assert.Equal(t, first.ID.String(), records[1][0])
+	assert.Equal(t, "+77040000002", records[2][2])
+
+	query.Set("format", "ndjson")
+	query.Set("sort", "-createdAt")
+	resp, err = http.Get(srv.baseURL + "/v1/employees:export?" + query.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var phones []string
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var emp domain.Employee
+		require.NoError(t, dec.Decode(&emp))
+		phones = append(phones, emp.Phone)
+	}
+	resp.Body.Close()
+	assert.Equal(t, []string{"+77040000002", "+77040000001"}, phones)
+
+	query.Set("format", "xlsx")
+	resp, err = http.Get(srv.baseURL + "/v1/employees:export?" + query.Encode())
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+
+	rows, err := xlsx.ReadRows(bytes.NewReader(body), int64(len(body)), 10)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Ерлан Касымов", rows[1][1])
+
+	resp, err = http.Get(srv.baseURL + "/v1/employees:export?format=pdf")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	svc := service.NewAPIKeyService(repository.NewAPIKeyRepository(pool))
+
+	expiresAt := time.Now().Add(time.Hour)
+	issued, err := svc.CreateAPIKey(ctx, domain.CreateAPIKeyRequest{
+		Name:      "payroll",
+		Scopes:    []string{"employees.read"},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	key, err := svc.AuthenticateAPIKey(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"employees.read"}, key.Scopes)
+
+	keys, err := svc.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].LastUsedAt)
+	assert.Equal(t, issued.Prefix, keys[0].Prefix)
+
+	rotated, err := svc.RotateAPIKey(ctx, issued.ID)
+	require.NoError(t, err)
+	_, err = svc.AuthenticateAPIKey(ctx, issued.Key)
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+
+	require.NoError(t, svc.RevokeAPIKey(ctx, issued.ID))
+	_, err = svc.AuthenticateAPIKey(ctx, rotated.Key)
+	assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+
+	_, err = svc.RotateAPIKey(ctx, issued.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
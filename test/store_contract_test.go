@@ -0,0 +1,22 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"employees-api/internal/repository"
+	"employees-api/internal/repository/repotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresEmployeeStore(t *testing.T) {
+	pool, closeDB := setupTestDB(t)
+	defer closeDB()
+
+	repotest.RunEmployeeStoreContract(t, func(t *testing.T) repository.EmployeeStore {
+		_, err := pool.Exec(context.Background(), "TRUNCATE employees")
+		require.NoError(t, err)
+		return repository.NewEmployeeRepository(pool)
+	})
+}
@@ -43,7 +43,7 @@ echo ""
 
 # 3. Unit тесты
 echo "=== 3. Unit тесты ==="
-if go test -v -race ./internal/service/...; then
+if go test -v -race ./internal/...; then
     log_success "Unit тесты пройдены"
 else
     log_error "Unit тесты провалились"